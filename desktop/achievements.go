@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	achievementsFile = "achievements.json"
+
+	// How long an unlock toast stays on screen, in ticks
+	toastDuration = 180
+)
+
+// Achievement condition types
+const (
+	conditionAnd          = "and"
+	conditionOr           = "or"
+	conditionDaysSurvived = "daysSurvived" // Value days survived in one run
+	conditionChoiceStreak = "choiceStreak" // Value consecutive Choice answers, optionally only to Speaker
+	conditionCardSequence = "cardSequence" // Cards played in this order during one run
+	conditionEnding       = "ending"       // Run ended with Ending
+	conditionAllEndings   = "allEndings"   // Every ending discovered
+	conditionState        = "state"        // Requirement holds for the current resources
+)
+
+var achievementConditionTypes = []string{
+	conditionAnd, conditionOr, conditionDaysSurvived, conditionChoiceStreak,
+	conditionCardSequence, conditionEnding, conditionAllEndings, conditionState,
+}
+
+// Achievement is a long-term goal declared by the deck
+type Achievement struct {
+	ID          string               `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Condition   AchievementCondition `json:"condition"`
+}
+
+// AchievementCondition is checked after every card. Type decides which of
+// the other fields are used.
+type AchievementCondition struct {
+	Type        string                 `json:"type"`
+	Conditions  []AchievementCondition `json:"conditions,omitempty"`  // For "and" and "or"
+	Value       int                    `json:"value,omitempty"`       // Days or streak length
+	Choice      string                 `json:"choice,omitempty"`      // choiceYes or choiceNo
+	Speaker     string                 `json:"speaker,omitempty"`     // Character ID the streak is counted for
+	Cards       []string               `json:"cards,omitempty"`       // Card IDs, not necessarily back to back
+	Ending      string                 `json:"ending,omitempty"`      // Ending ID
+	Requirement *Requirement           `json:"requirement,omitempty"` // Resource condition
+}
+
+// AchievementUnlock records when an achievement was earned
+type AchievementUnlock struct {
+	UnlockedAt time.Time `json:"unlockedAt"`
+	Day        int       `json:"day"`
+}
+
+// AchievementStore holds the unlocked achievements, keyed by ID
+type AchievementStore struct {
+	Unlocked map[string]*AchievementUnlock `json:"unlocked"`
+}
+
+func loadAchievements() *AchievementStore {
+	store := &AchievementStore{}
+	if err := loadUserJSON(profileFile(achievementsFile), store); err != nil {
+		log.Printf("Failed to load achievements: %v", err)
+	}
+	if store.Unlocked == nil {
+		store.Unlocked = make(map[string]*AchievementUnlock)
+	}
+	return store
+}
+
+func (s *AchievementStore) save() {
+	if err := saveUserJSON(profileFile(achievementsFile), s); err != nil {
+		log.Printf("Failed to save achievements: %v", err)
+	}
+}
+
+// checkAchievements unlocks every achievement whose condition now holds.
+// A casual run stops earning them once a decision was taken back, so
+// undoing can't be used to try every path.
+func (g *Game) checkAchievements() {
+	if g.player != nil || g.debugRun || g.undoCount > 0 {
+		return
+	}
+
+	unlocked := false
+	for _, achievement := range g.achievements {
+		if g.unlocks.Unlocked[achievement.ID] != nil {
+			continue
+		}
+		if !g.checkCondition(&achievement.Condition) {
+			continue
+		}
+
+		g.unlocks.Unlocked[achievement.ID] = &AchievementUnlock{UnlockedAt: g.clock.Now(), Day: g.resources.Day}
+		g.toasts = append(g.toasts, achievement.Name)
+		unlocked = true
+	}
+
+	if unlocked {
+		g.unlocks.save()
+	}
+}
+
+func (g *Game) checkCondition(cond *AchievementCondition) bool {
+	switch cond.Type {
+	case conditionAnd:
+		for i := range cond.Conditions {
+			if !g.checkCondition(&cond.Conditions[i]) {
+				return false
+			}
+		}
+		return len(cond.Conditions) > 0
+	case conditionOr:
+		for i := range cond.Conditions {
+			if g.checkCondition(&cond.Conditions[i]) {
+				return true
+			}
+		}
+		return false
+	case conditionDaysSurvived:
+		return g.resources.Day-1 >= cond.Value
+	case conditionChoiceStreak:
+		return g.choiceStreak(cond.Choice, cond.Speaker) >= cond.Value
+	case conditionCardSequence:
+		return g.playedInOrder(cond.Cards)
+	case conditionEnding:
+		return g.gameOver && g.endingID == cond.Ending
+	case conditionAllEndings:
+		return g.endings.Discovered() == len(allEndings)
+	case conditionState:
+		return cond.Requirement != nil && g.checkRequirements(cond.Requirement)
+	}
+	return false
+}
+
+// choiceStreak counts how many of the latest answers were the given choice.
+// With a speaker, only cards presented by that character are counted.
+func (g *Game) choiceStreak(choice, speaker string) int {
+	streak := 0
+	for i := len(g.journal) - 1; i >= 0; i-- {
+		turn := g.journal[i]
+		if turn.Choice == choiceInfo || (speaker != "" && turn.Speaker != speaker) {
+			continue
+		}
+		if turn.Choice != choice {
+			break
+		}
+		streak++
+	}
+	return streak
+}
+
+// playedInOrder reports whether the cards were played in this order
+func (g *Game) playedInOrder(cardIDs []string) bool {
+	if len(cardIDs) == 0 {
+		return false
+	}
+
+	next := 0
+	for _, turn := range g.journal {
+		if turn.CardID == cardIDs[next] {
+			next++
+			if next == len(cardIDs) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// updateToasts counts down the toast on screen and moves to the next one
+func (g *Game) updateToasts() {
+	if len(g.toasts) == 0 {
+		return
+	}
+
+	g.toastTicks++
+	if g.toastTicks >= toastDuration {
+		g.toasts = g.toasts[1:]
+		g.toastTicks = 0
+	}
+}
+
+func (g *Game) drawToast(screen *ebiten.Image) {
+	if len(g.toasts) == 0 {
+		return
+	}
+
+	l := g.layout
+	s := l.Scale
+	title := tr("achievements.unlocked")
+	name := g.toasts[0]
+
+	titleW, _ := getBoundsSize(smallFont, title)
+	nameW, _ := getBoundsSize(regularFont, name)
+	width := float64(max(titleW, nameW)) + 40*s
+	rect := Rect{X: (l.Width - width) / 2, Y: 60 * s, Width: width, Height: 56 * s}
+
+	// Fade in and out at the ends of the toast's time on screen
+	fade := float64(min(g.toastTicks, toastDuration-g.toastTicks)) / 20
+	opacity := min(fade, 1)
+
+	drawRoundedRect(screen, float32(rect.X), float32(rect.Y), float32(rect.Width), float32(rect.Height),
+		float32(10*s), withOpacity(colorPanel, opacity), true, 0)
+	drawTextWithOptions(screen, title, smallFont, int(rect.CenterX())-titleW/2, int(rect.Y+20*s), withOpacity(colorTextLight, opacity))
+	drawTextWithOptions(screen, name, regularFont, int(rect.CenterX())-nameW/2, int(rect.Y+44*s), withOpacity(colorTextLight, opacity))
+}
+
+// achievementsGeometry lays out the achievements panel and one row each
+func (g *Game) achievementsGeometry() (panel Rect, rows []Rect) {
+	l := g.layout
+	s := l.Scale
+	rowHeight := 60 * s
+
+	width := min(480*s, l.Width-20*s)
+	height := min(80*s+float64(len(g.achievements))*rowHeight+20*s, l.Height-20*s)
+	panel = Rect{X: (l.Width - width) / 2, Y: (l.Height - height) / 2, Width: width, Height: height}
+
+	y := panel.Y + 80*s
+	for range g.achievements {
+		rows = append(rows, Rect{X: panel.X + 20*s, Y: y, Width: width - 40*s, Height: rowHeight})
+		y += rowHeight
+	}
+	return panel, rows
+}
+
+func (g *Game) drawAchievementsScreen(screen *ebiten.Image) {
+	l := g.layout
+	s := l.Scale
+	panel, rows := g.achievementsGeometry()
+
+	// Draw overlay and panel
+	drawOverlay(screen, l)
+	vector.DrawFilledRect(screen, float32(panel.X), float32(panel.Y), float32(panel.Width), float32(panel.Height), colorPanel, true)
+
+	// Title and progress
+	title := tr("achievements.title")
+	w, _ := getBoundsSize(boldFont, title)
+	drawTextWithOptions(screen, title, boldFont, int(panel.CenterX())-w/2, int(panel.Y+40*s), colorTextLight)
+
+	unlockedCount := 0
+	for _, achievement := range g.achievements {
+		if g.unlocks.Unlocked[achievement.ID] != nil {
+			unlockedCount++
+		}
+	}
+	progress := fmt.Sprintf(tr("achievements.progress"), unlockedCount, len(g.achievements))
+	w, _ = getBoundsSize(smallFont, progress)
+	drawTextWithOptions(screen, progress, smallFont, int(panel.CenterX())-w/2, int(panel.Y+62*s), colorTextLight)
+
+	if len(g.achievements) == 0 {
+		drawWrappedText(screen, tr("achievements.empty"), regularFont,
+			int(panel.X+30*s), int(panel.Y+100*s), int(panel.Width-60*s), colorTextLight)
+		return
+	}
+
+	for i, achievement := range g.achievements {
+		row := rows[i]
+		if row.Y+row.Height > panel.Y+panel.Height {
+			break
+		}
+
+		// Locked achievements are dimmed
+		unlock := g.unlocks.Unlocked[achievement.ID]
+		clr := colorTextLight
+		status := tr("achievements.locked")
+		if unlock != nil {
+			status = unlock.UnlockedAt.Format("02.01.2006")
+		} else {
+			clr = withOpacity(colorTextLight, 0.5)
+		}
+
+		drawTextWithOptions(screen, achievement.Name, regularFont, int(row.X), int(row.Y+20*s), clr)
+		w, _ := getBoundsSize(smallFont, status)
+		drawTextWithOptions(screen, status, smallFont, int(row.X+row.Width)-w, int(row.Y+20*s), clr)
+		drawWrappedText(screen, achievement.Description, smallFont, int(row.X), int(row.Y+40*s), int(row.Width), clr)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import "testing"
+
+// turn is a journal entry for a card answered by the speaker
+func turn(cardID, speaker, choice string) JournalEntry {
+	return JournalEntry{CardID: cardID, Speaker: speaker, Choice: choice, CausedBy: -1}
+}
+
+func TestCheckCondition(t *testing.T) {
+	journal := []JournalEntry{
+		turn("A", "boss", choiceYes),
+		turn("B", "boss", choiceNo),
+		turn("C", "", choiceNo),
+		turn("D", "boss", choiceNo),
+		turn("E", "", choiceInfo),
+		turn("F", "teamLead", choiceYes),
+		turn("G", "boss", choiceNo),
+		turn("H", "", choiceNo),
+	}
+	streak := func(choice, speaker string, value int) AchievementCondition {
+		return AchievementCondition{Type: conditionChoiceStreak, Choice: choice, Speaker: speaker, Value: value}
+	}
+	sequence := func(cards ...string) AchievementCondition {
+		return AchievementCondition{Type: conditionCardSequence, Cards: cards}
+	}
+	always, never := streak(choiceNo, "", 1), streak(choiceYes, "", 1)
+
+	allReached := map[string]*EndingRecord{}
+	for _, ending := range allEndings {
+		allReached[ending] = &EndingRecord{Count: 1}
+	}
+	oneMissing := map[string]*EndingRecord{}
+	for _, ending := range allEndings[1:] {
+		oneMissing[ending] = &EndingRecord{Count: 1}
+	}
+
+	tests := []struct {
+		name    string
+		cond    AchievementCondition
+		endings map[string]*EndingRecord
+		want    bool
+	}{
+		{"and of true", AchievementCondition{Type: conditionAnd, Conditions: []AchievementCondition{always, always}}, nil, true},
+		{"and with false", AchievementCondition{Type: conditionAnd, Conditions: []AchievementCondition{always, never}}, nil, false},
+		{"empty and", AchievementCondition{Type: conditionAnd}, nil, false},
+		{"or with true", AchievementCondition{Type: conditionOr, Conditions: []AchievementCondition{never, always}}, nil, true},
+		{"or of false", AchievementCondition{Type: conditionOr, Conditions: []AchievementCondition{never, never}}, nil, false},
+		{"streak without speaker", streak(choiceNo, "", 1), nil, true},
+		{"streak without speaker stops at yes", streak(choiceNo, "", 3), nil, false},
+		{"streak skips other speakers", streak(choiceNo, "boss", 3), nil, true},
+		{"streak stops at the speaker's yes", streak(choiceNo, "boss", 4), nil, false},
+		{"streak of a speaker's yes", streak(choiceYes, "teamLead", 1), nil, true},
+		{"streak of an absent speaker", streak(choiceNo, "hr", 1), nil, false},
+		{"sequence with gaps", sequence("A", "D", "H"), nil, true},
+		{"sequence out of order", sequence("D", "A"), nil, false},
+		{"sequence of unplayed card", sequence("A", "Z"), nil, false},
+		{"empty sequence", sequence(), nil, false},
+		{"all endings", AchievementCondition{Type: conditionAllEndings}, allReached, true},
+		{"all endings but one", AchievementCondition{Type: conditionAllEndings}, oneMissing, false},
+		{"unknown type", AchievementCondition{Type: "luck"}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Game{journal: journal, endings: &EndingCollection{Endings: tt.endings}}
+			if got := g.checkCondition(&tt.cond); got != tt.want {
+				t.Errorf("checkCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,592 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Either end of an interval without a limit on that side
+const unbounded = 1 << 30
+
+// The welcome card takes the first day of every run
+const firstCardDay = 2
+
+// interval is the whole numbers from lo to hi, empty when lo > hi
+type interval struct {
+	lo, hi int
+}
+
+func (i interval) empty() bool {
+	return i.lo > i.hi
+}
+
+func (i interval) intersect(o interval) interval {
+	return interval{max(i.lo, o.lo), min(i.hi, o.hi)}
+}
+
+func (i interval) String() string {
+	switch {
+	case i.lo == i.hi:
+		return fmt.Sprint(i.lo)
+	case i.hi >= unbounded:
+		return fmt.Sprintf("%d or more", i.lo)
+	}
+	return fmt.Sprintf("%d-%d", i.lo, i.hi)
+}
+
+// statBox is a range of values per requirement resource, in the order of
+// requirementResources: the four stats, then the day
+type statBox [5]interval
+
+const dayIndex = 4
+
+func anyBox() statBox {
+	var b statBox
+	for i := range b {
+		b[i] = interval{-unbounded, unbounded}
+	}
+	return b
+}
+
+// aliveBox is every state a card can be drawn in, from the given day on:
+// a stat at either end has ended the run
+func aliveBox(day int) statBox {
+	var b statBox
+	for i := range 4 {
+		b[i] = interval{minValue + 1, maxValue - 1}
+	}
+	b[dayIndex] = interval{day, unbounded}
+	return b
+}
+
+func (b statBox) intersect(o statBox) (statBox, bool) {
+	for i := range b {
+		b[i] = b[i].intersect(o[i])
+		if b[i].empty() {
+			return b, false
+		}
+	}
+	return b, true
+}
+
+// Values meeting a comparison with v, and the comparison meeting the rest
+var (
+	comparisonIntervals = map[string]func(v int) interval{
+		"gt":  func(v int) interval { return interval{v + 1, unbounded} },
+		"gte": func(v int) interval { return interval{v, unbounded} },
+		"lt":  func(v int) interval { return interval{-unbounded, v - 1} },
+		"lte": func(v int) interval { return interval{-unbounded, v} },
+		"eq":  func(v int) interval { return interval{v, v} },
+	}
+	negatedComparisons = map[string]string{
+		"gt":  "lte",
+		"gte": "lt",
+		"lt":  "gte",
+		"lte": "gt",
+	}
+)
+
+// requirementBoxes returns boxes whose union is the states meeting the
+// requirement, or failing it when negate is set, the way checkRequirements
+// sees it
+func requirementBoxes(req *Requirement, negate bool) []statBox {
+	if req == nil {
+		if negate {
+			return nil
+		}
+		return []statBox{anyBox()}
+	}
+
+	if (req.Type == "and" || req.Type == "or") && len(req.Conditions) > 0 {
+		var boxes []statBox
+		if (req.Type == "and") != negate {
+			// Every condition: intersect the alternatives of each
+			boxes = []statBox{anyBox()}
+			for i := range req.Conditions {
+				var next []statBox
+				for _, a := range boxes {
+					for _, b := range requirementBoxes(&req.Conditions[i], negate) {
+						if box, ok := a.intersect(b); ok {
+							next = append(next, box)
+						}
+					}
+				}
+				boxes = next
+			}
+		} else {
+			for i := range req.Conditions {
+				boxes = append(boxes, requirementBoxes(&req.Conditions[i], negate)...)
+			}
+		}
+		return boxes
+	}
+
+	index := slices.Index(requirementResources, req.Resource)
+	_, ok := comparisonIntervals[req.Comparison]
+	if index < 0 || !ok {
+		// Never met
+		if negate {
+			return []statBox{anyBox()}
+		}
+		return nil
+	}
+
+	comparisons := []string{req.Comparison}
+	if negate {
+		comparisons = []string{negatedComparisons[req.Comparison]}
+		if req.Comparison == "eq" {
+			comparisons = []string{"lt", "gt"}
+		}
+	}
+	var boxes []statBox
+	for _, comparison := range comparisons {
+		box := anyBox()
+		box[index] = comparisonIntervals[comparison](req.Value)
+		boxes = append(boxes, box)
+	}
+	return boxes
+}
+
+// within keeps the parts of the boxes inside a context box
+func within(boxes []statBox, context statBox) []statBox {
+	var kept []statBox
+	for _, box := range boxes {
+		if box, ok := box.intersect(context); ok {
+			kept = append(kept, box)
+		}
+	}
+	return kept
+}
+
+// overlaps tells whether any state is in both unions of boxes
+func overlaps(a, b []statBox) bool {
+	for _, box := range b {
+		if len(within(a, box)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// statReach is how far a single card can move each stat, in display order
+type statReach struct {
+	up, down [4]int
+}
+
+func deckReach(deck *Deck) statReach {
+	var reach statReach
+	forEachCard(deck.definedCards(), func(card *Card) {
+		for _, effects := range []Effects{card.YesEffects, card.NoEffects, card.Effects} {
+			for i, change := range statChanges(effects) {
+				reach.up[i] = max(reach.up[i], change)
+				reach.down[i] = max(reach.down[i], -change)
+			}
+		}
+	})
+	return reach
+}
+
+// startBox is the states a run can start from, on the day of its first card
+func startBox() statBox {
+	box := aliveBox(firstCardDay)
+	launch, run := statValues(launchStart), statValues(runStart)
+	for i := range 4 {
+		box[i] = interval{min(launch[i], run[i]), max(launch[i], run[i])}
+	}
+	return box
+}
+
+// earliestDay is the first day a state in the box can come up, from the
+// states in from. Every card played moves the stats by at most the reach.
+// It returns 0 if the stats can't get there in time.
+func (r statReach) earliestDay(from, box statBox) int {
+	cards := 0
+	for i := range 4 {
+		switch {
+		case box[i].lo > from[i].hi:
+			if r.up[i] == 0 {
+				return 0
+			}
+			cards = max(cards, ceilDiv(box[i].lo-from[i].hi, r.up[i]))
+		case box[i].hi < from[i].lo:
+			if r.down[i] == 0 {
+				return 0
+			}
+			cards = max(cards, ceilDiv(from[i].lo-box[i].hi, r.down[i]))
+		}
+	}
+
+	day := max(box[dayIndex].lo, from[dayIndex].lo+cards)
+	if day > box[dayIndex].hi {
+		return 0
+	}
+	return day
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+// drift widens the stats of a box by the cards played over some days
+func (r statReach) drift(box statBox, days int) statBox {
+	for i := range 4 {
+		box[i] = interval{box[i].lo - days*r.down[i], box[i].hi + days*r.up[i]}
+	}
+	box[dayIndex] = interval{box[dayIndex].lo + days, box[dayIndex].hi}
+	return box
+}
+
+// afterAnswer returns the states an answer can leave the run in, from the
+// states the card was drawn in. The parts where a stat ends the run are
+// dropped, so no boxes means the answer always ends it.
+func afterAnswer(boxes []statBox, effects Effects) []statBox {
+	changes := statChanges(effects)
+	var after []statBox
+	for _, box := range boxes {
+		for i, change := range changes {
+			box[i] = interval{
+				clamp(box[i].lo+change, minValue, maxValue),
+				clamp(box[i].hi+change, minValue, maxValue),
+			}
+		}
+		box[dayIndex] = interval{box[dayIndex].lo + 1, box[dayIndex].hi}
+		after = append(after, within([]statBox{box}, aliveBox(firstCardDay))...)
+	}
+	return after
+}
+
+// answerEffects returns the effects of the answer an edge follows
+func answerEffects(card *Card, answer string) Effects {
+	switch answer {
+	case choiceYes:
+		return card.YesEffects
+	case choiceNo:
+		return card.NoEffects
+	}
+	return card.Effects
+}
+
+// describeStats writes the range of the resources a requirement checks
+// over some boxes, like "boss 40-60, day 12 or more"
+func describeStats(boxes []statBox, req *Requirement) string {
+	var parts []string
+	for i, resource := range requirementResources {
+		if !requirementChecks(req, resource) || len(boxes) == 0 {
+			continue
+		}
+		hull := interval{unbounded, -unbounded}
+		for _, box := range boxes {
+			hull = interval{min(hull.lo, box[i].lo), max(hull.hi, box[i].hi)}
+		}
+		parts = append(parts, resource+" "+hull.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// deckAnalysis is what can and can't happen with the cards of a deck
+type deckAnalysis struct {
+	deck     *Deck
+	graph    *storyGraph
+	reach    statReach
+	incoming map[*storyNode][]*storyEdge
+	earliest map[*storyNode]int // 0 when the card is never drawn
+	defined  map[string]*Card
+
+	unreachable, dead, alwaysTrue, late []error
+}
+
+func analyzeDeck(deck *Deck) *deckAnalysis {
+	a := &deckAnalysis{
+		deck:     deck,
+		graph:    buildStoryGraph(deck),
+		reach:    deckReach(deck),
+		incoming: make(map[*storyNode][]*storyEdge),
+		earliest: make(map[*storyNode]int),
+		defined:  make(map[string]*Card),
+	}
+	for _, edge := range a.graph.edges {
+		a.incoming[edge.to] = append(a.incoming[edge.to], edge)
+	}
+	for _, card := range deck.definedCards() {
+		a.defined[card.ID] = card
+	}
+
+	for _, node := range a.graph.nodes {
+		a.earliestDay(node)
+	}
+	for _, node := range a.graph.nodes {
+		a.checkCard(node)
+	}
+	return a
+}
+
+// errorf reports a problem with a card at the place it is defined
+func (a *deckAnalysis) errorf(card *Card, format string, args ...any) error {
+	var item any = card
+	if card.fromRef {
+		item = a.defined[card.ID]
+	}
+	return a.deck.errorf(item, "%s: %s", card.ID, fmt.Sprintf(format, args...))
+}
+
+// drawnIn returns the states a card can be drawn in, ignoring the day
+// it shows up at the earliest
+func (a *deckAnalysis) drawnIn(node *storyNode) []statBox {
+	return within(requirementBoxes(node.card.Requirements, false), aliveBox(firstCardDay))
+}
+
+// endsRun tells whether an answer to a card always ends the run, so the
+// followups of the answer are never queued
+func (a *deckAnalysis) endsRun(node *storyNode, answer string) bool {
+	if node.card.ID == competitorOfferCardID && answer == choiceYes {
+		return true
+	}
+	return len(afterAnswer(a.drawnIn(node), answerEffects(node.card, answer))) == 0
+}
+
+// live tells whether the followup of an edge can ever be queued
+func (a *deckAnalysis) live(edge *storyEdge) bool {
+	return a.earliestDay(edge.from) > 0 && edge.chance > 0 && !a.endsRun(edge.from, edge.answer)
+}
+
+// dueIn returns the states a run can be in when the followup of an edge is
+// due: after the answer, with the stats moved by the cards of the delay
+func (a *deckAnalysis) dueIn(edge *storyEdge) []statBox {
+	drawn := within(a.drawnIn(edge.from), aliveBox(a.earliestDay(edge.from)))
+	var due []statBox
+	for _, box := range afterAnswer(drawn, answerEffects(edge.from.card, edge.answer)) {
+		due = append(due, within([]statBox{a.reach.drift(box, edge.delay)}, aliveBox(firstCardDay))...)
+	}
+	return due
+}
+
+// earliestDay is the first day a card can be drawn: for cards in the
+// shuffle once the stats can meet the requirement, for followups once their
+// parent has been answered, the delay has passed and the stats can meet
+// the requirement from where the answer left them. Followups form a tree,
+// so the recursion ends.
+func (a *deckAnalysis) earliestDay(node *storyNode) int {
+	if day, ok := a.earliest[node]; ok {
+		return day
+	}
+
+	earliest := 0
+	consider := func(from statBox, boxes []statBox) {
+		for _, box := range boxes {
+			day := a.reach.earliestDay(from, box)
+			if day > 0 && (earliest == 0 || day < earliest) {
+				earliest = day
+			}
+		}
+	}
+	if node.card.MaxUses > 0 {
+		boxes := a.drawnIn(node)
+		if node.top {
+			consider(startBox(), boxes)
+			if node.card.ID == competitorOfferCardID {
+				// Also drawn once the run is won, whatever its requirement
+				win := aliveBox(winDay)
+				for i := range 4 {
+					win[i].lo = winStat
+				}
+				consider(startBox(), []statBox{win})
+			}
+		}
+		for _, edge := range a.incoming[node] {
+			if a.live(edge) {
+				for _, from := range a.dueIn(edge) {
+					consider(from, boxes)
+				}
+			}
+		}
+	}
+
+	a.earliest[node] = earliest
+	return earliest
+}
+
+// dueDay is the first day a card could come up if it had no requirement:
+// the first card of a run, or the day a followup is due
+func (a *deckAnalysis) dueDay(node *storyNode) int {
+	due := 0
+	if node.top {
+		due = firstCardDay
+	}
+	for _, edge := range a.incoming[node] {
+		if a.live(edge) {
+			day := a.earliestDay(edge.from) + 1 + edge.delay
+			if due == 0 || day < due {
+				due = day
+			}
+		}
+	}
+	return due
+}
+
+// checkCard records the problems of a card and of the answers leading to it
+func (a *deckAnalysis) checkCard(node *storyNode) {
+	card := node.card
+	req := card.Requirements
+	text := formatRequirement(req)
+
+	// Answers that end the run, reported once on the card answered
+	if a.earliestDay(node) > 0 {
+		lists := []struct {
+			answer string
+			cards  []*Card
+		}{
+			{choiceYes, card.YesFollowups},
+			{choiceNo, card.NoFollowups},
+			{"", card.Followups},
+		}
+		for _, list := range lists {
+			if len(list.cards) > 0 && a.endsRun(node, list.answer) {
+				answer := list.answer
+				if answer == "" {
+					answer = "ok"
+				}
+				a.dead = append(a.dead, a.errorf(card, "answering %s always ends the run, so its followups are never queued", answer))
+			}
+		}
+	}
+
+	for _, edge := range a.incoming[node] {
+		if a.earliestDay(edge.from) > 0 && edge.chance == 0 {
+			a.dead = append(a.dead, a.errorf(card, "never picked after %s, its probability is 0", a.answerName(edge)))
+		}
+	}
+
+	earliest := a.earliestDay(node)
+	if earliest == 0 {
+		var reason string
+		switch {
+		case card.MaxUses <= 0:
+			reason = "maxUses is 0"
+		case len(requirementBoxes(req, false)) == 0:
+			reason = fmt.Sprintf("requirement %s is contradictory", text)
+		case len(a.drawnIn(node)) == 0:
+			reason = fmt.Sprintf("requirement %s only holds once a stat has ended the run", text)
+		case !node.top && !slices.ContainsFunc(a.incoming[node], a.live):
+			reason = "it only follows cards and answers that never happen"
+		case !node.top:
+			reason = fmt.Sprintf("requirement %s can't be met once it is due, from day %d", text, a.dueDay(node))
+		default:
+			reason = fmt.Sprintf("the stats can't reach %s in time", text)
+		}
+		a.unreachable = append(a.unreachable, a.errorf(card, "never drawn, %s", reason))
+		return
+	}
+	if req == nil {
+		return
+	}
+
+	// Queued followups wait for their requirement, but say so when they
+	// can't be shown on the day they are due
+	for _, edge := range a.incoming[node] {
+		if !a.live(edge) {
+			continue
+		}
+		due := a.dueIn(edge)
+		if !overlaps(requirementBoxes(req, false), due) {
+			a.late = append(a.late, a.errorf(card, "can't be shown when due after %s, which leaves %s; it waits until %s", a.answerName(edge), describeStats(due, req), text))
+		}
+	}
+
+	// Compared with the states the card could come up in without it
+	context := aliveBox(a.dueDay(node))
+	if len(within(requirementBoxes(req, true), context)) == 0 {
+		a.alwaysTrue = append(a.alwaysTrue, a.errorf(card, "requirement %s always holds by day %d, the first day the card can come up", text, a.dueDay(node)))
+		return
+	}
+	a.checkConditions(card, req, context)
+}
+
+// checkConditions reports the conditions of a requirement that make no
+// difference: an or condition that never holds, or an and condition that
+// always does
+func (a *deckAnalysis) checkConditions(card *Card, req *Requirement, context statBox) {
+	for i := range req.Conditions {
+		condition := &req.Conditions[i]
+		text := formatRequirement(condition)
+		switch req.Type {
+		case "or":
+			if len(within(requirementBoxes(condition, false), context)) == 0 {
+				a.alwaysTrue = append(a.alwaysTrue, a.errorf(card, "condition %s of %s never holds", text, formatRequirement(req)))
+				continue
+			}
+		case "and":
+			if len(within(requirementBoxes(condition, true), context)) == 0 {
+				a.alwaysTrue = append(a.alwaysTrue, a.errorf(card, "condition %s of %s always holds", text, formatRequirement(req)))
+				continue
+			}
+		}
+		a.checkConditions(card, condition, context)
+	}
+}
+
+// answerName names the answer of an edge, like "BOSS_MEETING yes"
+func (a *deckAnalysis) answerName(edge *storyEdge) string {
+	if edge.answer == "" {
+		return edge.from.card.ID
+	}
+	return edge.from.card.ID + " " + edge.answer
+}
+
+// cardName names a card in the list of days, followups by their parent
+// since inline followups may share IDs
+func (a *deckAnalysis) cardName(node *storyNode) string {
+	if node.top || len(a.incoming[node]) == 0 {
+		return node.card.ID
+	}
+	return fmt.Sprintf("%s (after %s)", node.card.ID, a.answerName(a.incoming[node][0]))
+}
+
+func runAnalyze(args []string, assetDir string) error {
+	deck, err := readDeckArg(args, assetDir)
+	if err != nil {
+		return err
+	}
+	a := analyzeDeck(deck)
+
+	sections := []struct {
+		title    string
+		problems []error
+	}{
+		{"Unreachable cards", a.unreachable},
+		{"Dead branches", a.dead},
+		{"Requirements that always or never hold", a.alwaysTrue},
+		{"Followups not shown when due", a.late},
+	}
+	for _, section := range sections {
+		if len(section.problems) == 0 {
+			continue
+		}
+		fmt.Printf("%s:\n", section.title)
+		for _, problem := range section.problems {
+			fmt.Printf("  %v\n", problem)
+		}
+		fmt.Println()
+	}
+
+	// Earliest days, in the order cards can first come up
+	var nodes []*storyNode
+	for _, node := range a.graph.nodes {
+		if a.earliestDay(node) > 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return a.earliestDay(nodes[i]) < a.earliestDay(nodes[j])
+	})
+	fmt.Println("Earliest days:")
+	for _, node := range nodes {
+		fmt.Printf("  %3d  %s\n", a.earliestDay(node), a.cardName(node))
+	}
+
+	if problems := len(a.unreachable) + len(a.dead); problems > 0 {
+		return fmt.Errorf("%d unreachable cards and %d dead branches", len(a.unreachable), len(a.dead))
+	}
+	return nil
+}
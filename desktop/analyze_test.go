@@ -0,0 +1,161 @@
+package main
+
+import "testing"
+
+func cond(resource, comparison string, value int) Requirement {
+	return Requirement{Resource: resource, Comparison: comparison, Value: value}
+}
+
+func and(conditions ...Requirement) *Requirement {
+	return &Requirement{Type: "and", Conditions: conditions}
+}
+
+func or(conditions ...Requirement) *Requirement {
+	return &Requirement{Type: "or", Conditions: conditions}
+}
+
+func ptr(req Requirement) *Requirement {
+	return &req
+}
+
+// inBoxes tells whether the resources are in the union of the boxes
+func inBoxes(boxes []statBox, r Resources) bool {
+	values := [5]int{r.Motivation, r.Performance, r.Colleagues, r.Boss, r.Day}
+	for _, box := range boxes {
+		inside := true
+		for i, v := range values {
+			if v < box[i].lo || v > box[i].hi {
+				inside = false
+			}
+		}
+		if inside {
+			return true
+		}
+	}
+	return false
+}
+
+// Requirements with their edge cases: equality, the ends of the stat
+// range and conditions that contradict each other
+var boxRequirements = []struct {
+	name string
+	req  *Requirement
+}{
+	{"none", nil},
+	{"eq", ptr(cond("motivation", "eq", 50))},
+	{"eq 0", ptr(cond("motivation", "eq", 0))},
+	{"eq 100", ptr(cond("boss", "eq", 100))},
+	{"gt 0", ptr(cond("motivation", "gt", 0))},
+	{"gte 0", ptr(cond("motivation", "gte", 0))},
+	{"lt 100", ptr(cond("colleagues", "lt", 100))},
+	{"lte 100", ptr(cond("colleagues", "lte", 100))},
+	{"gt 100", ptr(cond("performance", "gt", 100))},
+	{"lt 0", ptr(cond("performance", "lt", 0))},
+	{"day", ptr(cond("day", "gte", 70))},
+	{"unknown resource", ptr(cond("luck", "gt", 10))},
+	{"unknown comparison", ptr(cond("motivation", "ne", 10))},
+	{"contradictory and", and(cond("motivation", "gt", 60), cond("motivation", "lt", 40))},
+	{"and of two eq", and(cond("motivation", "eq", 50), cond("motivation", "eq", 51))},
+	{"and over stats", and(cond("motivation", "gte", 60), cond("boss", "lte", 40), cond("day", "gt", 10))},
+	{"or", or(cond("motivation", "lt", 20), cond("motivation", "gt", 80))},
+	{"or of eq", or(cond("boss", "eq", 0), cond("boss", "eq", 100))},
+	{"nested", and(*or(cond("motivation", "eq", 50), cond("boss", "gt", 70)), cond("day", "lt", 30))},
+	{"empty and", &Requirement{Type: "and"}},
+}
+
+// Values around every threshold used above
+var boxSamples = []int{-1, 0, 1, 19, 20, 21, 39, 40, 41, 49, 50, 51, 59, 60, 61, 69, 70, 71, 79, 80, 81, 99, 100, 101}
+
+func TestRequirementBoxesMatchCheck(t *testing.T) {
+	for _, tt := range boxRequirements {
+		t.Run(tt.name, func(t *testing.T) {
+			met := requirementBoxes(tt.req, false)
+			failed := requirementBoxes(tt.req, true)
+			for _, v := range boxSamples {
+				for _, other := range []int{10, 50, 90} {
+					states := []Resources{
+						{Motivation: v, Performance: other, Colleagues: other, Boss: other, Day: other},
+						{Motivation: other, Performance: v, Colleagues: v, Boss: v, Day: v},
+					}
+					for _, r := range states {
+						g := &Game{resources: r}
+						want := g.checkRequirements(tt.req)
+						if got := inBoxes(met, r); got != want {
+							t.Errorf("%+v: in met boxes %v, checkRequirements %v", r, got, want)
+						}
+						if got := inBoxes(failed, r); got == want {
+							t.Errorf("%+v: in negated boxes %v, checkRequirements %v", r, got, want)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRequirementBoxesWhileAlive(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *Requirement
+		wantMet    int // Boxes left inside the states a card can be drawn in
+		wantFailed int
+	}{
+		{"eq splits its negation", ptr(cond("motivation", "eq", 50)), 1, 2},
+		{"eq 0 never holds", ptr(cond("motivation", "eq", 0)), 0, 1},
+		{"eq 100 never holds", ptr(cond("boss", "eq", 100)), 0, 1},
+		{"gt 0 always holds", ptr(cond("motivation", "gt", 0)), 1, 0},
+		{"lt 100 always holds", ptr(cond("colleagues", "lt", 100)), 1, 0},
+		{"gte 100 never holds", ptr(cond("performance", "gte", 100)), 0, 1},
+		{"lte 0 never holds", ptr(cond("performance", "lte", 0)), 0, 1},
+		{"contradictory and", and(cond("motivation", "gt", 60), cond("motivation", "lt", 40)), 0, 2},
+		{"or of both ends", or(cond("boss", "eq", 0), cond("boss", "eq", 100)), 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alive := aliveBox(firstCardDay)
+			if got := len(within(requirementBoxes(tt.req, false), alive)); got != tt.wantMet {
+				t.Errorf("met in %d boxes, want %d", got, tt.wantMet)
+			}
+			if got := len(within(requirementBoxes(tt.req, true), alive)); got != tt.wantFailed {
+				t.Errorf("failed in %d boxes, want %d", got, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestEarliestDay(t *testing.T) {
+	reach := statReach{up: [4]int{5, 5, 0, 5}, down: [4]int{5, 0, 5, 5}}
+	from := aliveBox(firstCardDay)
+	for i := range 4 {
+		from[i] = interval{50, 50}
+	}
+
+	tests := []struct {
+		name string
+		req  *Requirement
+		want int
+	}{
+		{"already met", ptr(cond("motivation", "eq", 50)), firstCardDay},
+		{"one card up", ptr(cond("motivation", "gt", 50)), firstCardDay + 1},
+		{"exact cards up", ptr(cond("motivation", "gte", 70)), firstCardDay + 4},
+		{"rounded up", ptr(cond("motivation", "gte", 71)), firstCardDay + 5},
+		{"cards down", ptr(cond("boss", "lte", 30)), firstCardDay + 4},
+		{"slowest stat decides", and(cond("motivation", "gte", 60), cond("boss", "lte", 20)), firstCardDay + 6},
+		{"day later than the cards", and(cond("motivation", "gte", 60), cond("day", "gte", 30)), 30},
+		{"no card raises it", ptr(cond("colleagues", "gt", 50)), 0},
+		{"no card lowers it", ptr(cond("performance", "lt", 50)), 0},
+		{"too late", and(cond("motivation", "gte", 80), cond("day", "lt", 5)), 0},
+		{"just in time", and(cond("motivation", "gte", 65), cond("day", "lte", 5)), 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boxes := requirementBoxes(tt.req, false)
+			if len(boxes) != 1 {
+				t.Fatalf("%d boxes, want 1", len(boxes))
+			}
+			if got := reach.earliestDay(from, boxes[0]); got != tt.want {
+				t.Errorf("earliestDay() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Default assets built into the binary, used for anything the asset
+// directories on disk don't provide
+//
+//go:embed assets
+var embeddedAssets embed.FS
+
+// Directory under the user data directory whose files override the defaults
+const userAssetsDir = "assets"
+
+var (
+	// Assets resolved through every layer, set up by initAssets
+	assets fs.FS = embeddedDefaults()
+
+	// Directories on disk searched before the embedded defaults, in order
+	assetDirs []string
+
+	// Problems loading assets, shown in game until dismissed
+	assetErrors []string
+)
+
+// initAssets sets up the lookup order: the directory given with -assets,
+// then the user data directory, then the embedded defaults
+func initAssets(overrideDir string) {
+	if err := checkAssetDir(overrideDir); err != nil {
+		reportAssetError(err)
+	}
+	assetDirs = assetLayerDirs(overrideDir)
+	assets = layeredAssets(assetDirs)
+}
+
+// checkAssetDir reports a directory given with -assets that doesn't exist
+func checkAssetDir(overrideDir string) error {
+	if overrideDir == "" {
+		return nil
+	}
+	if info, err := os.Stat(overrideDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("asset directory %s not found", overrideDir)
+	}
+	return nil
+}
+
+// assetLayerDirs lists the directories searched before the embedded
+// defaults, highest first
+func assetLayerDirs(overrideDir string) []string {
+	var dirs []string
+	if overrideDir != "" {
+		dirs = append(dirs, overrideDir)
+	}
+	if base, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(base, userDataDirName, userAssetsDir))
+	}
+	return dirs
+}
+
+// layeredAssets stacks the directories over the embedded defaults
+func layeredAssets(dirs []string) fs.FS {
+	var layers []fs.FS
+	for _, dir := range dirs {
+		layers = append(layers, os.DirFS(dir))
+	}
+	return &layeredFS{layers: append(layers, embeddedDefaults())}
+}
+
+func embeddedDefaults() fs.FS {
+	sub, err := fs.Sub(embeddedAssets, "assets")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// assetName turns a path from a data file into an asset name. Older files
+// name assets relative to the working directory, with an assets/ prefix.
+func assetName(name string) string {
+	return strings.TrimPrefix(filepath.ToSlash(name), "assets/")
+}
+
+// reportAssetError logs a failed asset and queues it to be shown in game
+func reportAssetError(err error) {
+	log.Printf("Failed to load asset: %v", err)
+	if msg := err.Error(); !slices.Contains(assetErrors, msg) {
+		assetErrors = append(assetErrors, msg)
+	}
+}
+
+// layeredFS looks every file up in each layer in turn, so upper layers
+// override single files without having to copy the rest
+type layeredFS struct {
+	layers []fs.FS
+}
+
+func (l *layeredFS) Open(name string) (fs.File, error) {
+	for _, layer := range l.layers {
+		f, err := layer.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir merges a directory across layers, upper layers hiding entries
+// with the same name
+func (l *layeredFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	var entries []fs.DirEntry
+	seen := make(map[string]bool)
+	found := false
+	for _, layer := range l.layers {
+		list, err := fs.ReadDir(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		for _, entry := range list {
+			if !seen[entry.Name()] {
+				seen[entry.Name()] = true
+				entries = append(entries, entry)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+// loadErrorsGeometry lays out the banner listing deck and asset problems
+func (g *Game) loadErrorsGeometry() (rect Rect, lines []string) {
+	l := g.layout
+	s := l.Scale
+
+	var blocks []string
+	if g.deckError != nil {
+		blocks = append(blocks, tr("deck.error")+"\n"+g.deckError.Error())
+	}
+	if len(assetErrors) > 0 {
+		blocks = append(blocks, tr("assets.error")+"\n"+strings.Join(assetErrors, "\n")+"\n"+tr("assets.dismiss"))
+	}
+	if len(blocks) == 0 {
+		return Rect{}, nil
+	}
+
+	width := l.Width - 20*s
+	lines = wrapText(strings.Join(blocks, "\n\n"), smallFont, int(width-20*s))
+	const maxLines = 12
+	if len(lines) > maxLines {
+		lines = append(lines[:maxLines-1], "...")
+	}
+	height := float64(len(lines)*lineHeightFor(smallFont)) + 16*s
+	return Rect{X: 10 * s, Y: 55 * s, Width: width, Height: height}, lines
+}
+
+// updateLoadErrors dismisses the asset problems when the banner is
+// clicked. Deck errors stay until the deck is fixed.
+func (g *Game) updateLoadErrors() bool {
+	if len(assetErrors) == 0 || !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return false
+	}
+
+	rect, _ := g.loadErrorsGeometry()
+	mx, my := ebiten.CursorPosition()
+	if !rect.Contains(float64(mx), float64(my)) {
+		return false
+	}
+	assetErrors = nil
+	return true
+}
+
+func (g *Game) drawLoadErrors(screen *ebiten.Image) {
+	rect, lines := g.loadErrorsGeometry()
+	if len(lines) == 0 {
+		return
+	}
+
+	s := g.layout.Scale
+	lineHeight := lineHeightFor(smallFont)
+	vector.DrawFilledRect(screen, float32(rect.X), float32(rect.Y), float32(rect.Width), float32(rect.Height), colorNoOption, true)
+	for i, line := range lines {
+		drawTextWithOptions(screen, line, smallFont, int(rect.X+10*s), int(rect.Y+20*s)+i*lineHeight, colorTextLight)
+	}
+}
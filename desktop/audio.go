@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+)
+
+// Sound effects played on game events
+const (
+	soundSwipe      = "swipe"
+	soundCardAppear = "card"
+	soundStatChange = "stat"
+	soundDanger     = "danger"
+	soundWin        = "win"
+	soundLoss       = "loss"
+)
+
+const audioSampleRate = 44100
+
+// Files in the sound directory for each effect
+var soundFiles = map[string]string{
+	soundSwipe:      "swipe.mp3",
+	soundCardAppear: "card.wav",
+	soundStatChange: "stat.wav",
+	soundDanger:     "danger.wav",
+	soundWin:        "win.wav",
+	soundLoss:       "loss.wav",
+}
+
+// Looping background track in the sound directory
+const musicFile = "music.wav"
+
+// AudioSettings are the player's sound preferences
+type AudioSettings struct {
+	SFXVolume   float64 `json:"sfxVolume"`
+	MusicVolume float64 `json:"musicVolume"`
+	Muted       bool    `json:"muted"`
+}
+
+// AudioManager plays sound effects and background music on Ebiten's
+// audio context
+type AudioManager struct {
+	context  *audio.Context
+	dir      string
+	sounds   map[string][]byte // Decoded PCM keyed by file path, nil if it failed
+	music    *audio.Player
+	settings AudioSettings
+}
+
+func newAudioManager(dir string, settings AudioSettings) *AudioManager {
+	a := &AudioManager{
+		context:  audio.NewContext(audioSampleRate),
+		dir:      dir,
+		sounds:   make(map[string][]byte),
+		settings: settings,
+	}
+
+	// Start background music
+	pcm := a.load(path.Join(dir, musicFile))
+	if pcm != nil {
+		loop := audio.NewInfiniteLoop(bytes.NewReader(pcm), int64(len(pcm)))
+		player, err := a.context.NewPlayer(loop)
+		if err != nil {
+			log.Printf("Failed to start music: %v", err)
+		} else {
+			a.music = player
+			a.applyMusicVolume()
+			a.music.Play()
+		}
+	}
+
+	return a
+}
+
+// play plays one of the named sound effects
+func (a *AudioManager) play(name string) {
+	if a == nil {
+		return
+	}
+	a.playFile(path.Join(a.dir, soundFiles[name]))
+}
+
+// playFile plays a sound effect from any asset, used for sounds attached
+// to cards by the deck
+func (a *AudioManager) playFile(name string) {
+	if a == nil || a.settings.Muted || a.settings.SFXVolume <= 0 {
+		return
+	}
+
+	pcm := a.load(name)
+	if pcm == nil {
+		return
+	}
+
+	player := a.context.NewPlayerFromBytes(pcm)
+	player.SetVolume(a.settings.SFXVolume)
+	player.Play()
+}
+
+// load decodes a sound file once and caches the result
+func (a *AudioManager) load(name string) []byte {
+	if pcm, ok := a.sounds[name]; ok {
+		return pcm
+	}
+
+	pcm, err := decodeSound(name)
+	if err != nil {
+		reportAssetError(fmt.Errorf("sound %s: %w", name, err))
+	}
+	a.sounds[name] = pcm
+	return pcm
+}
+
+// decodeSound decodes a whole sound file to PCM at the context's sample
+// rate, picking the decoder by file extension
+func decodeSound(name string) ([]byte, error) {
+	f, err := assets.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var stream io.Reader
+	switch strings.ToLower(path.Ext(name)) {
+	case ".mp3":
+		stream, err = mp3.DecodeWithSampleRate(audioSampleRate, f)
+	case ".wav":
+		stream, err = wav.DecodeWithSampleRate(audioSampleRate, f)
+	case ".ogg":
+		stream, err = vorbis.DecodeWithSampleRate(audioSampleRate, f)
+	default:
+		return nil, fmt.Errorf("unsupported sound format %q", path.Ext(name))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(stream)
+}
+
+// Settings returns the current sound preferences
+func (a *AudioManager) Settings() AudioSettings {
+	return a.settings
+}
+
+// SetSettings applies new sound preferences
+func (a *AudioManager) SetSettings(settings AudioSettings) {
+	a.settings = settings
+	a.applyMusicVolume()
+}
+
+func (a *AudioManager) applyMusicVolume() {
+	if a.music == nil {
+		return
+	}
+
+	if a.settings.Muted {
+		a.music.SetVolume(0)
+	} else {
+		a.music.SetVolume(a.settings.MusicVolume)
+	}
+}
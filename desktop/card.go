@@ -40,11 +40,21 @@ type Card struct {
 	MaxUses      int          `json:"maxUses"`
 	Uses         int          `json:"-"` // Not in JSON, tracked at runtime
 	ParentCardID string       `json:"parentCardId,omitempty"`
+	Image        string       `json:"image,omitempty"`   // Illustration, relative to the deck directory
+	Speaker      string       `json:"speaker,omitempty"` // ID of the character presenting the card
+	Sound        string       `json:"sound,omitempty"`   // Played when the card appears, relative to the deck directory
 
-	// Followup cards
-	YesFollowup interface{} `json:"yesFollowup,omitempty"`
-	NoFollowup  interface{} `json:"noFollowup,omitempty"`
-	Followup    interface{} `json:"followup,omitempty"`
+	// Followup candidates, one of which is picked by probability
+	YesFollowups []*Card `json:"yesFollowups,omitempty"`
+	NoFollowups  []*Card `json:"noFollowups,omitempty"`
+	Followups    []*Card `json:"followups,omitempty"` // For info cards
+
+	// Set on followup cards
+	Delay       int     `json:"delay,omitempty"`       // Days until the followup is shown, 0 for the next card, 1 if omitted
+	Probability float64 `json:"probability,omitempty"` // Relative weight among the other candidates
+	Ref         string  `json:"ref,omitempty"`         // ID of a card defined elsewhere to show instead
+
+	fromRef bool // Copy of the card a ref named, checked where it is defined
 }
 
 // FollowupCardItem represents a delayed followup card
@@ -52,4 +62,26 @@ type FollowupCardItem struct {
 	Card         *Card
 	ShowOnDay    int
 	ParentCardID string
+	ParentEntry  int // Journal entry of the decision that queued the card
+}
+
+// Character is a recurring person who presents cards
+type Character struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Portrait string   `json:"portrait,omitempty"` // Relative to the deck directory
+	Color    HexColor `json:"color"`
+}
+
+// Deck is a deck file. Older files, including plain arrays of cards, are
+// upgraded to the current schema version when they are decoded.
+type Deck struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	Include       []string       `json:"include,omitempty"` // Deck files or globs merged in, relative to this file
+	Characters    []*Character   `json:"characters,omitempty"`
+	Achievements  []*Achievement `json:"achievements,omitempty"`
+	Cards         []*Card        `json:"cards"`
+	FollowupCards []*Card        `json:"followupCards,omitempty"` // Only shown through refs, never shuffled in
+
+	sources map[any]sourcePos // Where each card, character and achievement is defined
 }
@@ -0,0 +1,16 @@
+package main
+
+import "time"
+
+// Clock tells the current time. The game reads time only through its
+// clock so the daily challenge can be tested on any date.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the real wall clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -1,15 +1,13 @@
 package main
 
 import (
-	"image"
 	"image/color"
-	"math/rand"
 
-	"github.com/hajimehoshi/ebiten/v2"
 	"golang.org/x/image/font"
 )
 
 const (
+	// Initial window size, also the reference size for the portrait layout
 	screenWidth  = 600
 	screenHeight = 900
 
@@ -17,6 +15,16 @@ const (
 	stateGame = iota
 	stateGameOver
 	stateAbout
+	stateSettings
+	stateMenu
+	stateStats
+	stateGallery
+	stateAchievements
+	stateJournal
+	stateDaily
+	stateProfiles
+	stateLeaderboard
+	statePacks
 
 	// Resource constants
 	minValue = 0
@@ -24,6 +32,21 @@ const (
 
 	// Card animation constants
 	swipeThreshold = 50
+	swipeDuration  = 30 // Ticks until a swiped card is answered, half a second
+
+	// Stats at or beyond these values play the danger sound
+	dangerLow  = 15
+	dangerHigh = 85
+
+	// The competitor offer is drawn once the day and every stat reach these
+	winDay  = 70
+	winStat = 70
+
+	// Card the win check draws, its yes answer wins the run
+	competitorOfferCardID = "COMPETITOR_JOB_OFFER"
+
+	// Difficulty of a run, recorded in replays
+	difficultyNormal = "normal"
 )
 
 var (
@@ -32,24 +55,27 @@ var (
 	smallFont   font.Face
 	emojiFace   font.Face
 
-	// Colors
-	colorBackground  = color.RGBA{243, 244, 246, 255}
-	colorCard        = color.RGBA{255, 255, 255, 255}
-	colorInfoCard    = color.RGBA{249, 250, 251, 255}
-	colorCardBorder  = color.RGBA{55, 65, 81, 255}
-	colorInfoBorder  = color.RGBA{107, 114, 128, 255}
-	colorMotivation  = color.RGBA{239, 68, 68, 180}   // Red
-	colorPerformance = color.RGBA{34, 197, 94, 180}   // Green
-	colorColleagues  = color.RGBA{234, 179, 8, 180}   // Yellow
-	colorBoss        = color.RGBA{59, 130, 246, 180}  // Blue
-	colorYesOption   = color.RGBA{34, 197, 94, 255}   // Green
-	colorNoOption    = color.RGBA{239, 68, 68, 255}   // Red
-	colorSwipeHint   = color.RGBA{107, 114, 128, 255} // Gray
-	colorRestartBtn  = color.RGBA{59, 130, 246, 255}  // Blue
-	colorTextPrimary = color.RGBA{0, 0, 0, 255}       // Black
-	colorTextLight   = color.RGBA{255, 255, 255, 255} // White
-
-	emptySubImage = ebiten.NewImage(3, 3).SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
-
-	random *rand.Rand
+	// Colors, assigned from the active theme by applyTheme
+	currentTheme          *Theme
+	colorBackground       color.RGBA
+	colorCard             color.RGBA
+	colorInfoCard         color.RGBA
+	colorCardBorder       color.RGBA
+	colorInfoBorder       color.RGBA
+	colorMotivation       color.RGBA
+	colorPerformance      color.RGBA
+	colorColleagues       color.RGBA
+	colorBoss             color.RGBA
+	colorYesOption        color.RGBA
+	colorNoOption         color.RGBA
+	colorSwipeHint        color.RGBA
+	colorRestartBtn       color.RGBA
+	colorRestartHover     color.RGBA
+	colorAboutBtn         color.RGBA
+	colorAboutHover       color.RGBA
+	colorPanel            color.RGBA
+	colorOverlay          color.RGBA
+	colorTextPrimary      color.RGBA
+	colorTextLight        color.RGBA
+	colorTextOnBackground color.RGBA
 )
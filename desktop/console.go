@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Lines of command output kept on screen
+const consoleOutputLines = 6
+
+// devConsole is the debug overlay for deck authors, toggled with F12 or
+// the backquote key
+type devConsole struct {
+	open   bool
+	input  []rune
+	output []string
+}
+
+// consoleCommand is one command the console understands
+type consoleCommand struct {
+	usage string
+	run   func(g *Game, args []string) error
+	fair  bool // Leaves the run on the records, the command marks it itself if needed
+}
+
+var consoleCommands = map[string]consoleCommand{
+	"card": {
+		usage: "card <id>",
+		run: func(g *Game, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			card := g.findCard(args[0])
+			if card == nil {
+				return fmt.Errorf("no card %s", args[0])
+			}
+			g.currentCard = card
+			g.currentCause = -1
+			g.cardX, g.cardY, g.cardRotation, g.cardOpacity = 0, 0, 0, 1
+			return nil
+		},
+	},
+	"set": {
+		usage: "set <motivation|performance|colleagues|boss|day> <value>",
+		run: func(g *Game, args []string) error {
+			if len(args) != 2 {
+				return errUsage
+			}
+			value, err := strconv.Atoi(args[1])
+			if err != nil {
+				return err
+			}
+			stat := g.resourcePointer(args[0])
+			if stat == nil {
+				return fmt.Errorf("no stat %s", args[0])
+			}
+			if args[0] == "day" {
+				*stat = max(value, 1)
+				return nil
+			}
+			*stat = clamp(value, minValue, maxValue)
+			g.checkGameOver()
+			return nil
+		},
+	},
+	"advance": {
+		usage: "advance <days>",
+		run: func(g *Game, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			days, err := strconv.Atoi(args[0])
+			if err != nil {
+				return err
+			}
+			g.resources.Day = max(g.resources.Day+days, 1)
+			return nil
+		},
+	},
+	"ending": {
+		usage: "ending <id>",
+		run: func(g *Game, args []string) error {
+			if len(args) != 1 || !slices.Contains(allEndings, args[0]) {
+				return fmt.Errorf("endings: %s", strings.Join(allEndings, ", "))
+			}
+			if g.gameOver {
+				return fmt.Errorf("the game is already over")
+			}
+			g.gameOver = true
+			g.state = stateGameOver
+			g.endingID = args[0]
+			g.gameWon = args[0] == endingBossHigh || args[0] == endingCompetitorOffer
+			g.onGameOver()
+			return nil
+		},
+	},
+	"reload": {
+		usage: "reload",
+		run: func(g *Game, _ []string) error {
+			hash := g.deckHash
+			if err := g.reloadDeck(); err != nil {
+				return err
+			}
+			if g.deckHash == hash {
+				g.consolePrint("deck unchanged")
+			} else {
+				g.consolePrint("deck reloaded, this run is no longer recorded")
+			}
+			return nil
+		},
+		// Only a deck that changed takes the run off the records
+		fair: true,
+	},
+}
+
+var errUsage = fmt.Errorf("wrong arguments")
+
+// findCard returns the first card with the given ID, followups included
+func (g *Game) findCard(id string) *Card {
+	var found *Card
+	forEachCard(g.cards, func(card *Card) {
+		if found == nil && card.ID == id {
+			found = card
+		}
+	})
+	return found
+}
+
+// resourcePointer returns the field of a stat by its requirement name
+func (g *Game) resourcePointer(name string) *int {
+	switch name {
+	case "motivation":
+		return &g.resources.Motivation
+	case "performance":
+		return &g.resources.Performance
+	case "colleagues":
+		return &g.resources.Colleagues
+	case "boss":
+		return &g.resources.Boss
+	case "day":
+		return &g.resources.Day
+	}
+	return nil
+}
+
+// printConsoleHelp lists the usage of every command
+func (g *Game) printConsoleHelp() {
+	usages := make([]string, 0, len(consoleCommands))
+	for _, command := range consoleCommands {
+		usages = append(usages, command.usage)
+	}
+	sort.Strings(usages)
+	for _, usage := range usages {
+		g.consolePrint(usage)
+	}
+}
+
+func (g *Game) consolePrint(line string) {
+	g.console.output = append(g.console.output, line)
+	if len(g.console.output) > consoleOutputLines {
+		g.console.output = g.console.output[len(g.console.output)-consoleOutputLines:]
+	}
+}
+
+// runConsoleCommand executes one typed line. A run changed from the
+// console is no longer a fair run and is left out of the records.
+func (g *Game) runConsoleCommand(line string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return
+	}
+	g.consolePrint("> " + line)
+
+	if fields[0] == "help" {
+		g.printConsoleHelp()
+		return
+	}
+	command, ok := consoleCommands[fields[0]]
+	if !ok {
+		g.consolePrint("unknown command, try help")
+		return
+	}
+	if !command.fair {
+		g.debugRun = true
+	}
+	if err := command.run(g, fields[1:]); err != nil {
+		g.consolePrint("error: " + err.Error())
+		g.consolePrint("usage: " + command.usage)
+	}
+}
+
+// toggleConsole opens or closes the console
+func (g *Game) toggleConsole() {
+	g.console.open = !g.console.open
+	g.console.input = nil
+}
+
+// updateConsole handles typing into the open console
+func (g *Game) updateConsole() {
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if r != '`' {
+			g.console.input = append(g.console.input, r)
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(g.console.input) > 0 {
+		g.console.input = g.console.input[:len(g.console.input)-1]
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter) {
+		line := string(g.console.input)
+		g.console.input = nil
+		g.runConsoleCommand(line)
+	}
+}
+
+func (g *Game) drawConsole(screen *ebiten.Image) {
+	if !g.console.open {
+		return
+	}
+
+	l := g.layout
+	s := l.Scale
+	width := min(380*s, l.Width*0.6)
+	vector.DrawFilledRect(screen, 0, 0, float32(width), float32(l.Height), withOpacity(colorPanel, 0.9), true)
+
+	// Engine state
+	r := g.resources
+	current := "-"
+	if g.currentCard != nil {
+		current = g.currentCard.ID
+	}
+	lines := []string{
+		fmt.Sprintf("day %d  motivation %d  performance %d  colleagues %d  boss %d",
+			r.Day, r.Motivation, r.Performance, r.Colleagues, r.Boss),
+		fmt.Sprintf("current %s", current),
+		fmt.Sprintf("availableCards %d / %d", len(g.availableCards), len(g.cards)),
+		"",
+		"delayedCards:",
+	}
+	for _, item := range g.delayedCards {
+		lines = append(lines, fmt.Sprintf("  %s day %d (from %s)", item.Card.ID, item.ShowOnDay, item.ParentCardID))
+	}
+
+	lines = append(lines, "", "uses:")
+	forEachCard(g.cards, func(card *Card) {
+		if card.Uses > 0 {
+			lines = append(lines, fmt.Sprintf("  %s %d/%d", card.ID, card.Uses, card.MaxUses))
+		}
+	})
+
+	lines = append(lines, "", fmt.Sprintf("playedCardIDs (%d):", len(g.playedCardIDs)))
+	lines = append(lines, "  "+strings.Join(g.playedCardIDs, ", "))
+
+	padding := int(10 * s)
+	drawWrappedText(screen, strings.Join(lines, "\n"), smallFont, padding, padding+int(12*s), int(width)-2*padding, colorTextLight)
+
+	// Command output and the input line at the bottom, drawn over the state
+	// so they stay readable when the state is long
+	lineHeight := lineHeightFor(smallFont)
+	bottom := append(append([]string{}, g.console.output...), "> "+string(g.console.input)+"_")
+	top := int(l.Height) - padding - len(bottom)*lineHeight
+	vector.DrawFilledRect(screen, 0, float32(top-lineHeight), float32(width), float32(l.Height)-float32(top-lineHeight), colorPanel, true)
+	for i, line := range bottom {
+		drawTextWithOptions(screen, firstLine(line, smallFont, int(width)-2*padding), smallFont, padding, top+i*lineHeight, colorTextLight)
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	dailyFile       = "daily.json"
+	dailyDateFormat = "2006-01-02"
+
+	// How many past days the daily screen lists
+	dailyHistoryShown = 7
+)
+
+// Game modes
+const (
+	modeStandard = "standard"
+	modeDaily    = "daily"
+	modeCasual   = "casual" // Decisions can be undone
+)
+
+// DailyResult is the single attempt at one day's challenge. It is stored
+// when the run starts so quitting doesn't grant a second attempt.
+type DailyResult struct {
+	Seed     int64  `json:"seed"`
+	Finished bool   `json:"finished"`
+	Days     int    `json:"days"`
+	Ending   string `json:"ending,omitempty"`
+	Won      bool   `json:"won"`
+}
+
+// DailyHistory holds the daily attempts, keyed by date
+type DailyHistory struct {
+	Results map[string]*DailyResult `json:"results"`
+}
+
+func loadDailyHistory() *DailyHistory {
+	history := &DailyHistory{}
+	if err := loadUserJSON(profileFile(dailyFile), history); err != nil {
+		log.Printf("Failed to load daily history: %v", err)
+	}
+	if history.Results == nil {
+		history.Results = make(map[string]*DailyResult)
+	}
+	return history
+}
+
+func (h *DailyHistory) save() {
+	if err := saveUserJSON(profileFile(dailyFile), h); err != nil {
+		log.Printf("Failed to save daily history: %v", err)
+	}
+}
+
+// dailyDate is the calendar date of t in local time
+func dailyDate(t time.Time) string {
+	return t.Format(dailyDateFormat)
+}
+
+// dailySeed derives the seed for a date and deck, so everyone with the same
+// deck gets the same cards on the same day
+func dailySeed(date, deckHash string) int64 {
+	sum := sha256.Sum256([]byte(date + "|" + deckHash))
+	return int64(binary.BigEndian.Uint64(sum[:8]))
+}
+
+// Streaks returns the current streak of consecutive days played, ending
+// today or yesterday, and the best streak ever
+func (h *DailyHistory) Streaks(today time.Time) (current, best int) {
+	dates := make([]string, 0, len(h.Results))
+	for date := range h.Results {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	var previous time.Time
+	streak := 0
+	for _, date := range dates {
+		day, err := time.ParseInLocation(dailyDateFormat, date, today.Location())
+		if err != nil {
+			continue
+		}
+		if streak > 0 && day.Equal(previous.AddDate(0, 0, 1)) {
+			streak++
+		} else {
+			streak = 1
+		}
+		best = max(best, streak)
+		previous = day
+	}
+
+	// The streak is only current if it reaches today or yesterday
+	last := dailyDate(previous)
+	if last == dailyDate(today) || last == dailyDate(today.AddDate(0, 0, -1)) {
+		current = streak
+	}
+	return current, best
+}
+
+// startDaily starts today's challenge if it hasn't been attempted yet
+func (g *Game) startDaily() {
+	date := dailyDate(g.clock.Now())
+	if g.daily.Results[date] != nil {
+		return
+	}
+
+	seed := dailySeed(date, g.deckHash)
+	g.daily.Results[date] = &DailyResult{Seed: seed}
+	g.daily.save()
+
+	g.player = nil
+	g.mode = modeDaily
+	g.dailyDate = date
+	g.resetRun(seed, runStart)
+}
+
+// recordDaily stores the result of a finished daily challenge
+func (g *Game) recordDaily() {
+	if g.mode != modeDaily {
+		return
+	}
+
+	result := g.daily.Results[g.dailyDate]
+	if result == nil {
+		result = &DailyResult{Seed: g.seed}
+		g.daily.Results[g.dailyDate] = result
+	}
+	result.Finished = true
+	result.Days = g.resources.Day - 1
+	result.Ending = g.endingID
+	result.Won = g.gameWon
+	g.daily.save()
+}
+
+// dailyGeometry lays out the daily challenge panel and its start button
+func (g *Game) dailyGeometry() (panel, startButton Rect) {
+	l := g.layout
+	s := l.Scale
+
+	width := min(440*s, l.Width-20*s)
+	height := 470 * s
+	panel = Rect{X: (l.Width - width) / 2, Y: (l.Height - height) / 2, Width: width, Height: height}
+	startButton = Rect{X: panel.CenterX() - 80*s, Y: panel.Y + height - 70*s, Width: 160 * s, Height: 44 * s}
+	return panel, startButton
+}
+
+// updateDaily handles input on the daily challenge screen
+func (g *Game) updateDaily() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.returnToGame()
+		return
+	}
+
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+
+	mx, my := ebiten.CursorPosition()
+	x, y := float64(mx), float64(my)
+	panel, startButton := g.dailyGeometry()
+
+	today := g.daily.Results[dailyDate(g.clock.Now())]
+	if today == nil && startButton.Contains(x, y) {
+		g.startDaily()
+		return
+	}
+
+	// Click outside the panel closes the screen
+	if !panel.Contains(x, y) {
+		g.returnToGame()
+	}
+}
+
+func (g *Game) drawDailyScreen(screen *ebiten.Image) {
+	l := g.layout
+	s := l.Scale
+	panel, startButton := g.dailyGeometry()
+	now := g.clock.Now()
+	date := dailyDate(now)
+
+	// Draw overlay and panel
+	drawOverlay(screen, l)
+	vector.DrawFilledRect(screen, float32(panel.X), float32(panel.Y), float32(panel.Width), float32(panel.Height), colorPanel, true)
+
+	// Title and date
+	title := tr("daily.title")
+	w, _ := getBoundsSize(boldFont, title)
+	drawTextWithOptions(screen, title, boldFont, int(panel.CenterX())-w/2, int(panel.Y+40*s), colorTextLight)
+	w, _ = getBoundsSize(smallFont, date)
+	drawTextWithOptions(screen, date, smallFont, int(panel.CenterX())-w/2, int(panel.Y+62*s), colorTextLight)
+
+	// Today's attempt, streaks and the last few days
+	current, best := g.daily.Streaks(now)
+	lines := []string{
+		g.dailyResultText(g.daily.Results[date], true),
+		"",
+		fmt.Sprintf(tr("daily.streak"), current, best),
+		"",
+		tr("daily.history"),
+	}
+	for i := 1; i <= dailyHistoryShown; i++ {
+		day := dailyDate(now.AddDate(0, 0, -i))
+		lines = append(lines, day+": "+g.dailyResultText(g.daily.Results[day], false))
+	}
+	drawWrappedText(screen, strings.Join(lines, "\n"), smallFont,
+		int(panel.X+30*s), int(panel.Y+95*s), int(panel.Width-60*s), colorTextLight)
+
+	// Start button, only while today's attempt is unused
+	if g.daily.Results[date] == nil {
+		mx, my := ebiten.CursorPosition()
+		g.drawButton(screen, Button{
+			X: startButton.X, Y: startButton.Y, Width: startButton.Width, Height: startButton.Height,
+			Text: tr("daily.start"), Color: colorRestartBtn, HoverColor: colorRestartHover, TextColor: colorTextLight,
+			IsHovered: startButton.Contains(float64(mx), float64(my)),
+		})
+	}
+}
+
+// dailyResultText describes one day's attempt
+func (g *Game) dailyResultText(result *DailyResult, today bool) string {
+	switch {
+	case result == nil && today:
+		return tr("daily.available")
+	case result == nil:
+		return "-"
+	case !result.Finished:
+		return tr("daily.abandoned")
+	case result.Won:
+		return fmt.Sprintf(tr("daily.won"), result.Days)
+	default:
+		return fmt.Sprintf(tr("daily.lost"), result.Days)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock stopped at a fixed time
+type fakeClock struct {
+	now time.Time
+}
+
+func (c fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestDailySeed(t *testing.T) {
+	at := func(year int, month time.Month, day, hour, minute int) Clock {
+		return fakeClock{now: time.Date(year, month, day, hour, minute, 0, 0, time.UTC)}
+	}
+
+	tests := []struct {
+		name         string
+		clockA       Clock
+		deckA        string
+		clockB       Clock
+		deckB        string
+		wantSameSeed bool
+	}{
+		{"same day and deck", at(2026, 3, 1, 0, 1), "deck", at(2026, 3, 1, 23, 59), "deck", true},
+		{"next day", at(2026, 3, 1, 23, 59), "deck", at(2026, 3, 2, 0, 0), "deck", false},
+		{"same day a year later", at(2026, 3, 1, 12, 0), "deck", at(2027, 3, 1, 12, 0), "deck", false},
+		{"other deck", at(2026, 3, 1, 12, 0), "deck", at(2026, 3, 1, 12, 0), "other", false},
+		{"empty deck hash", at(2026, 3, 1, 12, 0), "", at(2026, 3, 1, 12, 0), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := dailySeed(dailyDate(tt.clockA.Now()), tt.deckA)
+			b := dailySeed(dailyDate(tt.clockB.Now()), tt.deckB)
+			if (a == b) != tt.wantSameSeed {
+				t.Errorf("seeds %d and %d, want same: %v", a, b, tt.wantSameSeed)
+			}
+		})
+	}
+}
+
+func TestDailyStreaks(t *testing.T) {
+	tests := []struct {
+		name        string
+		played      []string
+		today       string
+		wantCurrent int
+		wantBest    int
+	}{
+		{"never played", nil, "2026-03-01", 0, 0},
+		{"played today", []string{"2026-03-01"}, "2026-03-01", 1, 1},
+		{"played yesterday", []string{"2026-02-28"}, "2026-03-01", 1, 1},
+		{"played two days ago", []string{"2026-02-27"}, "2026-03-01", 0, 1},
+		{"across a month", []string{"2026-01-30", "2026-01-31", "2026-02-01"}, "2026-02-01", 3, 3},
+		{"across a year", []string{"2025-12-30", "2025-12-31", "2026-01-01"}, "2026-01-02", 3, 3},
+		{"end of february", []string{"2026-02-27", "2026-02-28", "2026-03-01"}, "2026-03-01", 3, 3},
+		{"leap day", []string{"2028-02-28", "2028-02-29", "2028-03-01"}, "2028-03-01", 3, 3},
+		{"missing leap day", []string{"2028-02-28", "2028-03-01"}, "2028-03-01", 1, 1},
+		{"gap keeps best", []string{"2026-01-01", "2026-01-02", "2026-01-03", "2026-01-05"}, "2026-01-05", 1, 3},
+		{"gap then longer run", []string{"2026-01-01", "2026-01-03", "2026-01-04"}, "2026-01-05", 2, 2},
+		{"broken streak", []string{"2026-01-01", "2026-01-02"}, "2026-01-10", 0, 2},
+		{"same day next month", []string{"2026-01-15", "2026-02-15"}, "2026-02-15", 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := &DailyHistory{Results: make(map[string]*DailyResult)}
+			for _, date := range tt.played {
+				history.Results[date] = &DailyResult{Finished: true}
+			}
+			today, err := time.ParseInLocation(dailyDateFormat, tt.today, time.Local)
+			if err != nil {
+				t.Fatal(err)
+			}
+			clock := fakeClock{now: today.Add(15 * time.Hour)}
+
+			current, best := history.Streaks(clock.Now())
+			if current != tt.wantCurrent || best != tt.wantBest {
+				t.Errorf("Streaks() = %d, %d, want %d, %d", current, best, tt.wantCurrent, tt.wantBest)
+			}
+		})
+	}
+}
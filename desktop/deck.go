@@ -1,44 +1,74 @@
 package main
 
 import (
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"log"
-	"os"
+	"path"
 )
 
-func (g *Game) loadCards(filename string) error {
-	// Read the file
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		log.Printf("Failed to read cards file: %v", err)
-		// Fall back to sample cards if the file can't be read
-		g.loadSampleCards()
-		return err
-	}
+// Name of the base deck file in the asset root, without the extension that
+// picks its format
+const defaultDeckName = "deck"
 
-	// Parse JSON
-	var loadedCards []*Card
-	err = json.Unmarshal(data, &loadedCards)
+func (g *Game) loadCards(filename string) error {
+	g.deckFile = filename
+	deck, hash, err := g.buildDeck(filename)
+	g.deckModTime = deckModTime(g.deckWatch)
 	if err != nil {
-		log.Printf("Failed to parse cards JSON: %v", err)
-		// Fall back to sample cards if JSON parsing fails
+		log.Printf("Failed to load cards: %v", err)
+		g.deckError = err
+		// Fall back to sample cards so the game stays playable while the
+		// error is shown
 		g.loadSampleCards()
 		return err
 	}
+	g.deckError = nil
 
-	if len(loadedCards) == 0 {
-		log.Print("Card file contained no valid cards, using sample cards")
-		g.loadSampleCards()
-		return nil
-	}
+	// Fingerprint the deck so recorded runs can tell decks apart
+	g.deckHash = hash
+	g.applyDeck(deck)
 
 	// Set cards and initialize available cards
-	g.cards = loadedCards
+	g.cards = deck.Cards
 	g.resetAvailableCards()
 
 	return nil
 }
 
+// applyDeck indexes the characters and achievements of a deck and resolves
+// images next to the deck file
+func (g *Game) applyDeck(deck *Deck) {
+	g.characters = make(map[string]*Character, len(deck.Characters))
+	for _, character := range deck.Characters {
+		g.characters[character.ID] = character
+	}
+	g.achievements = deck.Achievements
+	g.deckDir = path.Dir(g.deckFile)
+	g.images = newImageCache(g.deckDir)
+}
+
+// hashDeck returns a short fingerprint of the deck file contents
+func hashDeck(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// parseDeck decodes a deck file of any schema version, in the format of
+// the file extension, and notes where each card is defined
+func parseDeck(filename string, data []byte) (*Deck, error) {
+	var deck Deck
+	doc, from, report, err := decodeDeckFile(filename, data, &deck)
+	if err != nil {
+		return nil, err
+	}
+	if from < deckSchemaVersion {
+		log.Printf("Deck %s uses schema version %d, run \"deck migrate\" to upgrade it (%d changes)", filename, from, len(report))
+	}
+	deck.recordSources(filename, doc)
+	return &deck, nil
+}
+
 // Fallback function to load sample cards if JSON loading fails
 func (g *Game) loadSampleCards() {
 	sampleCards := []*Card{
@@ -70,6 +100,7 @@ func (g *Game) loadSampleCards() {
 	}
 
 	g.cards = sampleCards
+	g.deckHash = "sample"
 	g.resetAvailableCards()
 }
 
@@ -78,7 +109,7 @@ func (g *Game) resetAvailableCards() {
 	copy(g.availableCards, g.cards)
 
 	// Reset uses count
-	for _, card := range g.cards {
+	forEachCard(g.cards, func(card *Card) {
 		card.Uses = 0
-	}
+	})
 }
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// deckCommand is a deck authoring tool, run as "office-politics deck <name>"
+// instead of starting the game
+type deckCommand struct {
+	usage   string
+	summary string
+	run     func(args []string, assetDir string) error
+}
+
+var deckCommands = map[string]deckCommand{
+	"analyze": {
+		usage:   "analyze [deck]",
+		summary: "Find cards that can never be drawn, dead followups and requirements that always hold, and print the earliest day of every card",
+		run:     runAnalyze,
+	},
+	"migrate": {
+		usage:   "migrate [-n] <deck>...",
+		summary: "Upgrade deck files to the current schema version and report every change",
+		run:     runMigrate,
+	},
+	"convert": {
+		usage:   "convert <input> <output>",
+		summary: "Convert a deck between JSON and YAML, or from TOML, by file extension",
+		run:     runConvert,
+	},
+	"graph": {
+		usage:   "graph [-format dot|mermaid|svg] [-card <id>] [-resource <name>] [-o <file>] [deck]",
+		summary: "Draw the cards and followups of a deck as a graph",
+		run:     runGraph,
+	},
+	"schema": {
+		usage:   "schema [-kind deck|pack|manifest] [-o <file>]",
+		summary: "Write a JSON Schema of the deck format for editors to validate against",
+		run:     runSchema,
+	},
+}
+
+// runDeckCommand runs a deck tool and returns the process exit code. The
+// tools read the default deck through assetDir like the game, without
+// touching the game's assets.
+func runDeckCommand(args []string, assetDir string) int {
+	if len(args) == 0 {
+		printDeckUsage()
+		return 2
+	}
+
+	command, ok := deckCommands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown deck command %q\n", args[0])
+		printDeckUsage()
+		return 2
+	}
+	if err := command.run(args[1:], assetDir); err != nil {
+		fmt.Fprintf(os.Stderr, "deck %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+func printDeckUsage() {
+	names := make([]string, 0, len(deckCommands))
+	for name := range deckCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "usage: office-politics deck <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	for _, name := range names {
+		command := deckCommands[name]
+		fmt.Fprintf(os.Stderr, "  %s\n        %s\n", command.usage, command.summary)
+	}
+}
+
+// readDeckArg reads the deck a tool was given, includes and all. Without
+// one it reads the deck the game would load with -assets assetDir.
+func readDeckArg(args []string, assetDir string) (*Deck, error) {
+	switch len(args) {
+	case 0:
+		if err := checkAssetDir(assetDir); err != nil {
+			return nil, err
+		}
+		fsys := layeredAssets(assetLayerDirs(assetDir))
+		deck, _, _, err := readDeck(fsys, findDeckFile(fsys, ".", defaultDeckName))
+		return deck, err
+	case 1:
+		// Includes are resolved inside the directory of the deck root
+		fsys := os.DirFS(filepath.Dir(args[0]))
+		deck, _, _, err := readDeck(fsys, filepath.Base(args[0]))
+		return deck, err
+	}
+	return nil, errors.New("give at most one deck file")
+}
+
+func runMigrate(args []string, _ string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := flags.Bool("n", false, "Report the changes without writing the files")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("no deck files given")
+	}
+
+	failed := 0
+	for _, filename := range flags.Args() {
+		if err := migrateDeckFile(filename, *dryRun); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filename, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, flags.NArg())
+	}
+	return nil
+}
+
+// migrateDeckFile rewrites one deck file at the current schema version
+func migrateDeckFile(filename string, dryRun bool) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	doc, err := decodeDeckDocument(filename, data)
+	if err != nil {
+		return err
+	}
+	deck, from, report, err := upgradeDeckDocument(doc)
+	if err != nil {
+		return err
+	}
+	if from == deckSchemaVersion {
+		fmt.Printf("%s: already at schema version %d\n", filename, deckSchemaVersion)
+		return nil
+	}
+
+	fmt.Printf("%s: schema version %d -> %d, %d changes\n", filename, from, deckSchemaVersion, len(report))
+	for _, change := range report {
+		fmt.Printf("  %s\n", change)
+	}
+	if dryRun {
+		return nil
+	}
+
+	upgraded, err := encodeDeckDocument(filename, deck)
+	if err != nil {
+		return err
+	}
+	upgraded = matchFinalNewline(data, upgraded)
+	if hasComments(filename, data) {
+		fmt.Printf("  comments are not kept in the rewritten file\n")
+	}
+	return writeFileAtomic(filename, upgraded)
+}
+
+func runConvert(args []string, _ string) error {
+	if len(args) != 2 {
+		return errors.New("need an input and an output file")
+	}
+	input, output := args[0], args[1]
+
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	doc, err := decodeDeckDocument(input, data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+	converted, err := encodeDeckDocument(output, doc)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to write anything that doesn't read back as the same deck
+	back, err := decodeDeckDocument(output, converted)
+	if err != nil {
+		return fmt.Errorf("converted deck doesn't read back: %w", err)
+	}
+	want, _ := json.Marshal(doc)
+	got, _ := json.Marshal(back)
+	if !bytes.Equal(want, got) {
+		return fmt.Errorf("converting %s to %s would change the deck", path.Ext(input), path.Ext(output))
+	}
+	return writeFileAtomic(output, matchFinalNewline(data, converted))
+}
+
+// matchFinalNewline drops the newline the encoders end with when the
+// original file didn't end with one
+func matchFinalNewline(original, encoded []byte) []byte {
+	if !bytes.HasSuffix(original, []byte("\n")) {
+		return bytes.TrimSuffix(encoded, []byte("\n"))
+	}
+	return encoded
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Deck file extensions in the order they are looked for when a directory
+// has more than one
+var deckExtensions = []string{".json", ".yaml", ".yml", ".toml"}
+
+// Decoders of the deck file formats into documents: *jsonObject, []any,
+// string, json.Number, bool and nil values
+var deckDecoders = map[string]func(data []byte) (any, error){
+	".json": func(data []byte) (any, error) {
+		doc, err := decodeJSONDocument(data)
+		if err != nil {
+			return nil, positionedError(data, err)
+		}
+		return doc, nil
+	},
+	".yaml": decodeYAMLDocument,
+	".yml":  decodeYAMLDocument,
+	".toml": decodeTOMLDocument,
+}
+
+// Encoders of the formats the deck tools can write. TOML has no null and
+// no top-level arrays, so it is read only.
+var deckEncoders = map[string]func(doc any) ([]byte, error){
+	".json": encodeJSONDocument,
+	".yaml": encodeYAMLDocument,
+	".yml":  encodeYAMLDocument,
+}
+
+// decodeDeckDocument decodes a deck file with the decoder for its extension
+func decodeDeckDocument(filename string, data []byte) (any, error) {
+	decode, ok := deckDecoders[strings.ToLower(path.Ext(filename))]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown deck format, use one of %s", filename, strings.Join(deckExtensions, ", "))
+	}
+	return decode(data)
+}
+
+// encodeDeckDocument writes a document in the format of the file extension
+func encodeDeckDocument(filename string, doc any) ([]byte, error) {
+	encode, ok := deckEncoders[strings.ToLower(path.Ext(filename))]
+	if !ok {
+		return nil, fmt.Errorf("%s: can't write %s decks", filename, path.Ext(filename))
+	}
+	return encode(doc)
+}
+
+// findDeckFile returns the deck file called base in dir, in whichever
+// format the highest layer of fsys has it. Without one it names the JSON file.
+func findDeckFile(fsys fs.FS, dir, base string) string {
+	layers := []fs.FS{fsys}
+	if l, ok := fsys.(*layeredFS); ok {
+		layers = l.layers
+	}
+	for _, layer := range layers {
+		for _, ext := range deckExtensions {
+			name := path.Join(dir, base+ext)
+			if _, err := fs.Stat(layer, name); err == nil {
+				return name
+			}
+		}
+	}
+	return path.Join(dir, base+deckExtensions[0])
+}
+
+// decodeYAMLDocument decodes YAML into the same values decodeJSONDocument
+// returns, keeping key order and number text
+func decodeYAMLDocument(data []byte) (any, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) == 0 {
+		return nil, errors.New("empty document")
+	}
+	return yamlValue(root.Content[0])
+}
+
+func yamlValue(node *yaml.Node) (any, error) {
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("line %d, column %d: %s", node.Line, node.Column, fmt.Sprintf(format, args...))
+	}
+
+	switch node.Kind {
+	case yaml.AliasNode:
+		return yamlValue(node.Alias)
+	case yaml.MappingNode:
+		obj := newJSONObject()
+		obj.line, obj.column = node.Line, node.Column
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Kind != yaml.ScalarNode || key.ShortTag() == "!!merge" {
+				return nil, fail("keys must be plain strings")
+			}
+			v, err := yamlValue(value)
+			if err != nil {
+				return nil, err
+			}
+			obj.Set(key.Value, v)
+		}
+		return obj, nil
+	case yaml.SequenceNode:
+		arr := []any{}
+		for _, item := range node.Content {
+			v, err := yamlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			return nil, nil
+		case "!!bool":
+			var b bool
+			if err := node.Decode(&b); err != nil {
+				return nil, fail("%v", err)
+			}
+			return b, nil
+		case "!!int", "!!float":
+			// Keep the text of numbers JSON can spell, like encoding/json
+			if isJSONNumber(node.Value) {
+				return json.Number(node.Value), nil
+			}
+			var f float64
+			if err := node.Decode(&f); err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+				return nil, fail("%q is not a finite number", node.Value)
+			}
+			return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+		case "!!binary":
+			return nil, fail("binary values are not supported")
+		}
+		return node.Value, nil
+	}
+	return nil, fail("unexpected YAML node")
+}
+
+func isJSONNumber(s string) bool {
+	var n json.Number
+	return json.Unmarshal([]byte(s), &n) == nil && n.String() == s
+}
+
+// encodeYAMLDocument writes a decoded document as YAML with two-space
+// indentation. Multi-line strings become literal blocks, and strings that
+// would read back as another type are quoted, so decoding gives the same
+// document.
+func encodeYAMLDocument(doc any) ([]byte, error) {
+	node, err := yamlNode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Words YAML 1.1 tools read as booleans. They are plain strings in YAML
+// 1.2 but get quoted anyway, so older editors and linters agree.
+var yaml11Booleans = []string{"y", "n", "yes", "no", "on", "off"}
+
+func yamlNode(v any) (*yaml.Node, error) {
+	switch v := v.(type) {
+	case *jsonObject:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range v.keys {
+			value, err := yamlNode(v.values[key])
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+		}
+		return node, nil
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if len(v) == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		for _, item := range v {
+			value, err := yamlNode(item)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		return node, nil
+	case string:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+		if slices.Contains(yaml11Booleans, strings.ToLower(v)) {
+			node.Style = yaml.DoubleQuotedStyle
+		}
+		return node, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return nil, fmt.Errorf("unexpected %T in document", v)
+}
+
+// hasComments tells whether a YAML deck has comments, which the deck tools
+// can't keep when they rewrite it
+func hasComments(filename string, data []byte) bool {
+	if ext := strings.ToLower(path.Ext(filename)); ext != ".yaml" && ext != ".yml" {
+		return false
+	}
+
+	var root yaml.Node
+	if yaml.Unmarshal(data, &root) != nil {
+		return false
+	}
+	var visit func(node *yaml.Node) bool
+	visit = func(node *yaml.Node) bool {
+		if node.HeadComment != "" || node.LineComment != "" || node.FootComment != "" {
+			return true
+		}
+		return slices.ContainsFunc(node.Content, visit)
+	}
+	return visit(&root)
+}
+
+// decodeTOMLDocument decodes TOML into document values. TOML tables have
+// no order, so keys come out sorted.
+func decodeTOMLDocument(data []byte) (any, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			line, column := decodeErr.Position()
+			return nil, fmt.Errorf("line %d, column %d: %w", line, column, err)
+		}
+		return nil, err
+	}
+	return tomlValue(doc)
+}
+
+func tomlValue(v any) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		obj := newJSONObject()
+		for _, key := range keys {
+			value, err := tomlValue(v[key])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			obj.Set(key, value)
+		}
+		return obj, nil
+	case []any:
+		arr := make([]any, 0, len(v))
+		for _, item := range v {
+			value, err := tomlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		return arr, nil
+	case int64:
+		return json.Number(strconv.FormatInt(v, 10)), nil
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return nil, fmt.Errorf("%v is not a finite number", v)
+		}
+		return json.Number(strconv.FormatFloat(v, 'g', -1, 64)), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case toml.LocalDate, toml.LocalTime, toml.LocalDateTime:
+		return fmt.Sprint(v), nil
+	}
+	return v, nil
+}
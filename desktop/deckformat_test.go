@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// A deck with the values most likely to change on the way through YAML:
+// number text, strings that look like other types, non-ASCII text,
+// line breaks, key order and empty containers
+const roundTripDeck = `{
+    "schemaVersion": 2,
+    "zeta": "keys keep their order",
+    "cards": [
+        {
+            "id": "ÇAY_MOLASI",
+            "text": "Çay molası mı?\nİkinci satır: \"alıntı\" ve # işaret",
+            "yesText": "yes",
+            "noText": "no",
+            "speaker": "null",
+            "maxUses": 0,
+            "weight": 1.50,
+            "big": 1e3,
+            "negative": -7,
+            "numberText": "10",
+            "on": true,
+            "off": false,
+            "missing": null,
+            "yesEffects": {},
+            "noFollowups": [],
+            "tags": [
+                "~",
+                "- dash",
+                ": colon",
+                "  padded  ",
+                ""
+            ]
+        }
+    ]
+}
+`
+
+func TestConvertRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []string // Extensions the deck is converted through, in order
+	}{
+		{"json to json", []string{".json", ".json"}},
+		{"json to yaml and back", []string{".json", ".yaml", ".json"}},
+		{"json to yml and back", []string{".json", ".yml", ".json"}},
+		{"yaml to yaml", []string{".json", ".yaml", ".yaml", ".json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			previous := filepath.Join(dir, "deck0"+tt.steps[0])
+			if err := os.WriteFile(previous, []byte(roundTripDeck), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			for i, ext := range tt.steps[1:] {
+				next := filepath.Join(dir, "deck"+string(rune('1'+i))+ext)
+				if err := runConvert([]string{previous, next}, ""); err != nil {
+					t.Fatalf("convert to %s: %v", ext, err)
+				}
+				previous = next
+			}
+
+			got, err := os.ReadFile(previous)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != roundTripDeck {
+				t.Errorf("deck changed on the way through %v:\n%s", tt.steps, got)
+			}
+		})
+	}
+}
+
+func TestConvertKeepsFinalNewline(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		ext   string
+	}{
+		{"json with newline", "{\"cards\": []}\n", ".json"},
+		{"json without newline", "{\"cards\": []}", ".json"},
+		{"yaml with newline", "cards: []\n", ".yaml"},
+		{"yaml without newline", "cards: []", ".yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			input := filepath.Join(dir, "in"+tt.ext)
+			if err := os.WriteFile(input, []byte(tt.input), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			for _, ext := range []string{".json", ".yaml"} {
+				output := filepath.Join(dir, "out"+ext)
+				if err := runConvert([]string{input, output}, ""); err != nil {
+					t.Fatal(err)
+				}
+				got, err := os.ReadFile(output)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := bytes.HasSuffix([]byte(tt.input), []byte("\n"))
+				if bytes.HasSuffix(got, []byte("\n")) != want {
+					t.Errorf("%s output %q, want final newline: %v", ext, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// sourcePos is where a card, character or achievement is defined
+type sourcePos struct {
+	file         string
+	line, column int
+}
+
+func (p sourcePos) String() string {
+	if p.line == 0 {
+		return p.file
+	}
+	return fmt.Sprintf("%s: line %d, column %d", p.file, p.line, p.column)
+}
+
+// errorf prefixes an error about a deck item with where it is defined
+func (d *Deck) errorf(item any, format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	if pos, ok := d.sources[item]; ok {
+		return fmt.Errorf("%s: %w", pos, err)
+	}
+	return err
+}
+
+// recordSources notes where the cards, characters and achievements of a
+// decoded deck file start, walking the document alongside the structs
+func (d *Deck) recordSources(filename string, doc *jsonObject) {
+	d.sources = make(map[any]sourcePos)
+	record := func(item any, v any) {
+		if obj, ok := v.(*jsonObject); ok {
+			d.sources[item] = sourcePos{filename, obj.line, obj.column}
+		}
+	}
+
+	var cards func(list []*Card, v any)
+	cards = func(list []*Card, v any) {
+		items, _ := v.([]any)
+		for i, card := range list {
+			if i >= len(items) {
+				return
+			}
+			record(card, items[i])
+			if obj, ok := items[i].(*jsonObject); ok {
+				cards(card.YesFollowups, obj.values["yesFollowups"])
+				cards(card.NoFollowups, obj.values["noFollowups"])
+				cards(card.Followups, obj.values["followups"])
+			}
+		}
+	}
+	cards(d.Cards, doc.values["cards"])
+	cards(d.FollowupCards, doc.values["followupCards"])
+
+	characters, _ := doc.values["characters"].([]any)
+	for i, character := range d.Characters {
+		if i < len(characters) {
+			record(character, characters[i])
+		}
+	}
+	achievements, _ := doc.values["achievements"].([]any)
+	for i, achievement := range d.Achievements {
+		if i < len(achievements) {
+			record(achievement, achievements[i])
+		}
+	}
+}
+
+// assembleDeck reads a deck root from fsys and every file it includes, depth first
+// in include order, and merges them into one deck with followup refs
+// resolved. It returns the contents of every file for the fingerprint,
+// and the files and globbed directories to watch for changes, even when
+// assembling fails.
+func assembleDeck(fsys fs.FS, filename string) (deck *Deck, data []byte, watch []string, err error) {
+	deck = &Deck{sources: make(map[any]sourcePos)}
+	read := make(map[string]bool)
+
+	var include func(filename string) error
+	include = func(filename string) error {
+		// Each file is read once, so a glob may match the file using it
+		if read[filename] {
+			return nil
+		}
+		read[filename] = true
+		watch = append(watch, filename)
+
+		fileData, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			return err
+		}
+		part, err := parseDeck(filename, fileData)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+		data = append(data, fileData...)
+		deck.merge(part)
+
+		for _, pattern := range part.Include {
+			pattern = path.Join(path.Dir(filename), pattern)
+			matches, err := fs.Glob(fsys, pattern)
+			if err != nil {
+				return fmt.Errorf("%s: include %s: %w", filename, pattern, err)
+			}
+			if strings.ContainsAny(pattern, "*?[") {
+				// Files added to the directory change its modification time
+				watch = append(watch, path.Dir(pattern))
+			}
+			matches = slices.DeleteFunc(matches, func(match string) bool {
+				_, ok := deckDecoders[strings.ToLower(path.Ext(match))]
+				return !ok
+			})
+			if len(matches) == 0 {
+				return fmt.Errorf("%s: include %s matches no deck files", filename, pattern)
+			}
+			for _, match := range matches {
+				if err := include(match); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := include(filename); err != nil {
+		return nil, data, watch, err
+	}
+	deck.Include = nil
+
+	if err := errors.Join(checkDuplicateIDs(deck), resolveFollowupRefs(deck)); err != nil {
+		return nil, data, watch, err
+	}
+	return deck, data, watch, nil
+}
+
+// merge appends the content of an included deck file
+func (d *Deck) merge(part *Deck) {
+	d.SchemaVersion = part.SchemaVersion
+	d.Include = part.Include
+	d.Characters = append(d.Characters, part.Characters...)
+	d.Achievements = append(d.Achievements, part.Achievements...)
+	d.Cards = append(d.Cards, part.Cards...)
+	d.FollowupCards = append(d.FollowupCards, part.FollowupCards...)
+	for item, pos := range part.sources {
+		d.sources[item] = pos
+	}
+}
+
+// definedCards returns the cards at the top of the deck files, the ones a
+// ref can name
+func (d *Deck) definedCards() []*Card {
+	return slices.Concat(d.Cards, d.FollowupCards)
+}
+
+// checkDuplicateIDs reports cards, characters and achievements defined
+// twice at the top of the deck files. Inline followups may share IDs, but
+// a ref has to name exactly one card.
+func checkDuplicateIDs(deck *Deck) error {
+	var errs []error
+	check := func(kind string, ids map[string]any, id string, item any) {
+		if id == "" {
+			return
+		}
+		if first, ok := ids[id]; ok {
+			errs = append(errs, deck.errorf(item, "duplicate %s ID %s, first defined at %s", kind, id, deck.sources[first]))
+			return
+		}
+		ids[id] = item
+	}
+
+	cards := make(map[string]any)
+	for _, card := range deck.definedCards() {
+		check("card", cards, card.ID, card)
+	}
+	characters := make(map[string]any)
+	for _, character := range deck.Characters {
+		check("character", characters, character.ID, character)
+	}
+	achievements := make(map[string]any)
+	for _, achievement := range deck.Achievements {
+		check("achievement", achievements, achievement.ID, achievement)
+	}
+	return errors.Join(errs...)
+}
+
+// resolveFollowupRefs replaces every followup with a ref by a copy of the
+// card it names, keeping the delay and probability of the ref. Refs may
+// name any card at the top of a deck file. Followups can't loop back to
+// a card that led to them, since the game walks followups as a tree.
+func resolveFollowupRefs(deck *Deck) error {
+	defined := make(map[string]*Card)
+	for _, card := range deck.definedCards() {
+		if card.Ref != "" {
+			return deck.errorf(card, "only followups can use ref, define the card here instead")
+		}
+		if defined[card.ID] == nil {
+			defined[card.ID] = card
+		}
+	}
+
+	// Refs found in the followups of a card, inline followups included
+	var refsOf func(cards []*Card, refs []*Card) []*Card
+	refsOf = func(cards []*Card, refs []*Card) []*Card {
+		for _, card := range cards {
+			if card.Ref != "" {
+				refs = append(refs, card)
+				continue
+			}
+			refs = refsOf(card.YesFollowups, refs)
+			refs = refsOf(card.NoFollowups, refs)
+			refs = refsOf(card.Followups, refs)
+		}
+		return refs
+	}
+
+	var errs []error
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[*Card]int)
+	var visit func(card *Card, trail []string)
+	visit = func(card *Card, trail []string) {
+		state[card] = visiting
+		trail = append(trail, card.ID)
+		for _, ref := range refsOf([]*Card{card}, nil) {
+			rest := *ref
+			rest.Ref, rest.Delay, rest.Probability = "", 0, 0
+			// The decoder gives every followup maxUses 1, any other value
+			// was written on the ref
+			if rest.MaxUses == 1 {
+				rest.MaxUses = 0
+			}
+			target := defined[ref.Ref]
+			switch {
+			case !reflect.DeepEqual(rest, Card{}):
+				errs = append(errs, deck.errorf(ref, "followup with ref %s can only set delay and probability", ref.Ref))
+			case target == nil:
+				errs = append(errs, deck.errorf(ref, "followup refers to unknown card %s", ref.Ref))
+			case state[target] == visiting:
+				errs = append(errs, deck.errorf(ref, "followups loop back to %s: %s -> %s", ref.Ref, strings.Join(trail, " -> "), ref.Ref))
+			case state[target] == 0:
+				visit(target, trail)
+			}
+		}
+		state[card] = visited
+	}
+	for _, card := range deck.definedCards() {
+		if state[card] == 0 {
+			visit(card, nil)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	// The copies share the followup lists of their card, so resolving the
+	// lists in place resolves every copy
+	var resolve func(cards []*Card)
+	resolve = func(cards []*Card) {
+		for i, card := range cards {
+			if card.Ref != "" {
+				resolved := *defined[card.Ref]
+				resolved.Delay, resolved.Probability = card.Delay, card.Probability
+				resolved.fromRef = true
+				cards[i] = &resolved
+				continue
+			}
+			resolve(card.YesFollowups)
+			resolve(card.NoFollowups)
+			resolve(card.Followups)
+		}
+	}
+	resolve(deck.Cards)
+	resolve(deck.FollowupCards)
+	return nil
+}
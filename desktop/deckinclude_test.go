@@ -0,0 +1,149 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAssembleDeck(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		wantIDs   []string // Top-level card IDs in order
+		wantWatch []string
+		wantErr   string
+	}{
+		{
+			name: "glob includes in name order",
+			files: map[string]string{
+				"deck.json":       `{"schemaVersion": 2, "include": ["cards/*"], "cards": [{"id": "R", "text": "r"}]}`,
+				"cards/b.yaml":    "schemaVersion: 2\ncards:\n  - id: B\n    text: b\n",
+				"cards/a.json":    `{"schemaVersion": 2, "cards": [{"id": "A", "text": "a"}]}`,
+				"cards/notes.txt": "not a deck",
+			},
+			wantIDs:   []string{"R", "A", "B"},
+			wantWatch: []string{"deck.json", "cards", "cards/a.json", "cards/b.yaml"},
+		},
+		{
+			name: "glob matching the including file",
+			files: map[string]string{
+				"deck.json":  `{"schemaVersion": 2, "include": ["*.json"], "cards": [{"id": "R", "text": "r"}]}`,
+				"extra.json": `{"schemaVersion": 2, "cards": [{"id": "E", "text": "e"}]}`,
+			},
+			wantIDs:   []string{"R", "E"},
+			wantWatch: []string{"deck.json", ".", "extra.json"},
+		},
+		{
+			name: "glob matching nothing",
+			files: map[string]string{
+				"deck.json": `{"schemaVersion": 2, "include": ["cards/*.json"], "cards": [{"id": "R", "text": "r"}]}`,
+			},
+			wantErr: "deck.json: include cards/*.json matches no deck files",
+		},
+		{
+			name: "duplicate IDs across files",
+			files: map[string]string{
+				"deck.json": "{\"schemaVersion\": 2, \"include\": [\"more.json\"], \"cards\": [\n  {\"id\": \"A\", \"text\": \"a\"}\n]}",
+				"more.json": "{\"schemaVersion\": 2,\n\"followupCards\": [{\"id\": \"A\", \"text\": \"again\"}],\n\"characters\": [{\"id\": \"boss\"}, {\"id\": \"boss\"}]}",
+			},
+			wantErr: "more.json: line 2, column 19: duplicate card ID A, first defined at deck.json: line 2, column 3\n" +
+				"more.json: line 3, column 32: duplicate character ID boss, first defined at more.json: line 3, column 16",
+		},
+		{
+			name: "ref to a card of another file",
+			files: map[string]string{
+				"deck.json": `{"schemaVersion": 2, "include": ["more.json"], "cards": [{"id": "R", "text": "r", "yesFollowups": [{"ref": "F", "delay": 3, "probability": 0.5}]}]}`,
+				"more.json": `{"schemaVersion": 2, "followupCards": [{"id": "F", "text": "f", "maxUses": 2}]}`,
+			},
+			wantIDs:   []string{"R", "F"},
+			wantWatch: []string{"deck.json", "more.json"},
+		},
+		{
+			name: "unknown ref",
+			files: map[string]string{
+				"deck.json": `{"schemaVersion": 2, "cards": [{"id": "R", "text": "r", "noFollowups": [{"ref": "NOPE"}]}]}`,
+			},
+			wantErr: "deck.json: line 1, column 73: followup refers to unknown card NOPE",
+		},
+		{
+			name: "ref setting maxUses",
+			files: map[string]string{
+				"deck.json": `{"schemaVersion": 2, "cards": [{"id": "R", "text": "r", "noFollowups": [{"ref": "F", "maxUses": 3}]}], "followupCards": [{"id": "F", "text": "f"}]}`,
+			},
+			wantErr: "deck.json: line 1, column 73: followup with ref F can only set delay and probability",
+		},
+		{
+			name: "ref setting text",
+			files: map[string]string{
+				"deck.json": `{"schemaVersion": 2, "cards": [{"id": "R", "text": "r", "noFollowups": [{"ref": "F", "text": "other"}]}], "followupCards": [{"id": "F", "text": "f"}]}`,
+			},
+			wantErr: "deck.json: line 1, column 73: followup with ref F can only set delay and probability",
+		},
+		{
+			name: "ref loop",
+			files: map[string]string{
+				"deck.json": `{"schemaVersion": 2, "cards": [{"id": "A", "text": "a", "yesFollowups": [{"ref": "B"}]}], "followupCards": [{"id": "B", "text": "b", "followups": [{"id": "C", "text": "c", "noFollowups": [{"ref": "A"}]}]}]}`,
+			},
+			wantErr: "deck.json: line 1, column 189: followups loop back to A: A -> B -> A",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, data := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(data)}
+			}
+
+			deck, _, watch, err := assembleDeck(fsys, "deck.json")
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error\n%v\nwant\n%s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, card := range deck.definedCards() {
+				ids = append(ids, card.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("cards %v, want %v", ids, tt.wantIDs)
+			}
+			if !slices.Equal(watch, tt.wantWatch) {
+				t.Errorf("watch %v, want %v", watch, tt.wantWatch)
+			}
+		})
+	}
+}
+
+func TestResolvedRefKeepsItsDelay(t *testing.T) {
+	fsys := fstest.MapFS{"deck.json": {Data: []byte(`{"schemaVersion": 2,
+		"cards": [{"id": "R", "text": "r", "yesFollowups": [{"ref": "F", "delay": 3, "probability": 0.5}, {"ref": "F"}]}],
+		"followupCards": [{"id": "F", "text": "f", "maxUses": 2, "delay": 5}]}`)}}
+
+	deck, _, _, err := assembleDeck(fsys, "deck.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		card            *Card
+		wantDelay       int
+		wantProbability float64
+	}{
+		{deck.Cards[0].YesFollowups[0], 3, 0.5},
+		{deck.Cards[0].YesFollowups[1], 1, 0},
+	}
+	for i, tt := range tests {
+		if tt.card.ID != "F" || tt.card.Text != "f" || tt.card.MaxUses != 2 {
+			t.Errorf("followup %d = %+v, want a copy of F", i, tt.card)
+		}
+		if tt.card.Delay != tt.wantDelay || tt.card.Probability != tt.wantProbability {
+			t.Errorf("followup %d: delay %d, probability %v, want %d, %v", i, tt.card.Delay, tt.card.Probability, tt.wantDelay, tt.wantProbability)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Current version of the deck file format. Files without a schemaVersion
+// are version 1.
+//
+// Version 2 replaced the singular yesFollowup, noFollowup and followup
+// fields with lists, and made an omitted maxUses mean 1 for every card,
+// as the web client always read it. The desktop client never showed
+// top-level cards without maxUses but showed followups with maxUses 0
+// once, so the upgrade sets those to 1.
+const deckSchemaVersion = 2
+
+// deckUpgrades turn a deck of the version in the key into the next version,
+// reporting every change they make
+var deckUpgrades = map[int]func(deck *jsonObject, report *[]string){
+	1: upgradeDeckV1,
+}
+
+// Keys of a deck or pack deck that hold lists of cards
+var deckCardLists = []string{"cards", "followupCards", "override"}
+
+// Singular followup fields of version 1 and the lists replacing them
+var singularFollowups = [][2]string{
+	{"yesFollowup", "yesFollowups"},
+	{"noFollowup", "noFollowups"},
+	{"followup", "followups"},
+}
+
+// decodeDeckFile decodes a deck or pack deck of any schema version and
+// file format into v, upgrading it in memory. It returns the upgraded
+// document, the version the file has and what a migration would change.
+func decodeDeckFile(filename string, data []byte, v any) (*jsonObject, int, []string, error) {
+	doc, err := decodeDeckDocument(filename, data)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	deck, from, report, err := upgradeDeckDocument(doc)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	applyDeckDefaults(deck)
+
+	upgraded, err := json.Marshal(deck)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if err := json.Unmarshal(upgraded, v); err != nil {
+		return nil, 0, nil, fieldError(deck, err)
+	}
+	return deck, from, report, nil
+}
+
+// fieldError adds the position of the object holding a field of the wrong
+// type, since the offsets of the error are in the upgraded document
+func fieldError(doc *jsonObject, err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+
+	var v any = doc
+	line, column := doc.line, doc.column
+	for _, key := range strings.Split(typeErr.Field, ".") {
+		switch container := v.(type) {
+		case *jsonObject:
+			v = container.values[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i >= len(container) {
+				v = nil
+				break
+			}
+			v = container[i]
+		}
+		if obj, ok := v.(*jsonObject); ok && obj.line > 0 {
+			line, column = obj.line, obj.column
+		}
+	}
+	if line == 0 {
+		return err
+	}
+	return fmt.Errorf("line %d, column %d: %w", line, column, err)
+}
+
+// upgradeDeckDocument brings a decoded deck file to the current schema
+// version and returns the version it had
+func upgradeDeckDocument(doc any) (deck *jsonObject, from int, report []string, err error) {
+	switch d := doc.(type) {
+	case []any:
+		deck = newJSONObject()
+		deck.Set("cards", d)
+		report = append(report, "card array wrapped in a deck object")
+	case *jsonObject:
+		deck = d
+	default:
+		return nil, 0, nil, errors.New("deck must be an object or an array of cards")
+	}
+
+	from = 1
+	if v, ok := deck.Get("schemaVersion"); ok {
+		n, ok := v.(json.Number)
+		version, err := n.Int64()
+		if !ok || err != nil || version < 1 {
+			return nil, 0, nil, fmt.Errorf("schemaVersion must be a positive whole number, got %v", v)
+		}
+		from = int(version)
+	}
+	if from > deckSchemaVersion {
+		return nil, 0, nil, fmt.Errorf("deck schema version %d is newer than supported version %d", from, deckSchemaVersion)
+	}
+
+	for version := from; version < deckSchemaVersion; version++ {
+		deckUpgrades[version](deck, &report)
+	}
+	deck.SetFirst("schemaVersion", json.Number(strconv.Itoa(deckSchemaVersion)))
+	return deck, from, report, nil
+}
+
+// upgradeDeckV1 moves the singular followup fields into the followup lists
+// and keeps followups with maxUses 0 shown once
+func upgradeDeckV1(deck *jsonObject, report *[]string) {
+	var upgrade func(cards []any, followup bool)
+	upgrade = func(cards []any, followup bool) {
+		for _, item := range cards {
+			card, ok := item.(*jsonObject)
+			if !ok {
+				continue
+			}
+			id, _ := card.values["id"].(string)
+
+			for _, keys := range singularFollowups {
+				singular, list := keys[0], keys[1]
+				v, ok := card.Get(singular)
+				if !ok {
+					continue
+				}
+
+				var moved []any
+				switch v := v.(type) {
+				case []any:
+					moved = v
+				case *jsonObject:
+					moved = []any{v}
+				}
+				if len(moved) == 0 {
+					card.Delete(singular)
+					*report = append(*report, fmt.Sprintf("%s: empty %s removed", id, singular))
+					continue
+				}
+				if existing, ok := card.values[list].([]any); ok {
+					card.Set(list, append(existing, moved...))
+					card.Delete(singular)
+				} else {
+					card.Rename(singular, list)
+					card.Set(list, moved)
+				}
+				*report = append(*report, fmt.Sprintf("%s: %s moved to %s", id, singular, list))
+			}
+
+			maxUses, hasMaxUses := card.values["maxUses"].(json.Number)
+			switch {
+			case followup && hasMaxUses && maxUses.String() == "0":
+				card.Set("maxUses", json.Number("1"))
+				*report = append(*report, fmt.Sprintf("%s: maxUses 0 on a followup set to 1", id))
+			case !followup && !hasMaxUses:
+				// Nothing to rewrite, but the desktop client starts showing the card
+				*report = append(*report, fmt.Sprintf("%s: omitted maxUses now means 1, version 1 never showed the card", id))
+			}
+
+			for _, keys := range singularFollowups {
+				list, _ := card.values[keys[1]].([]any)
+				upgrade(list, true)
+			}
+		}
+	}
+
+	for _, key := range deckCardLists {
+		cards, _ := deck.values[key].([]any)
+		upgrade(cards, false)
+	}
+}
+
+// applyDeckDefaults fills in the fields a current deck may leave out
+func applyDeckDefaults(deck *jsonObject) {
+	for _, key := range deckCardLists {
+		cards, _ := deck.values[key].([]any)
+		applyCardDefaults(cards, false)
+	}
+}
+
+// applyCardDefaults fills in the fields the cards and their followups may
+// leave out. Followups without a delay come a day later, as in the web
+// client.
+func applyCardDefaults(cards []any, followup bool) {
+	for _, item := range cards {
+		card, ok := item.(*jsonObject)
+		if !ok {
+			continue
+		}
+		if _, ok := card.Get("maxUses"); !ok {
+			card.Set("maxUses", json.Number("1"))
+		}
+		if _, ok := card.Get("delay"); followup && !ok {
+			card.Set("delay", json.Number("1"))
+		}
+		for _, keys := range singularFollowups {
+			list, _ := card.values[keys[1]].([]any)
+			applyCardDefaults(list, true)
+		}
+	}
+}
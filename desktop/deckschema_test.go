@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUpgradeDeckDocument(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       string // Upgraded deck, compact
+		wantFrom   int
+		wantReport []string
+		wantErr    string
+	}{
+		{
+			name:     "singular followups become lists",
+			input:    `{"cards": [{"id": "A", "maxUses": 1, "yesFollowup": {"id": "B", "maxUses": 2}, "noFollowup": [{"id": "C", "maxUses": 1}]}]}`,
+			want:     `{"schemaVersion":2,"cards":[{"id":"A","maxUses":1,"yesFollowups":[{"id":"B","maxUses":2}],"noFollowups":[{"id":"C","maxUses":1}]}]}`,
+			wantFrom: 1,
+			wantReport: []string{
+				"A: yesFollowup moved to yesFollowups",
+				"A: noFollowup moved to noFollowups",
+			},
+		},
+		{
+			name:       "singular followup joins an existing list",
+			input:      `{"cards": [{"id": "A", "maxUses": 1, "followups": [{"id": "B", "maxUses": 1}], "followup": {"id": "C", "maxUses": 1}}]}`,
+			want:       `{"schemaVersion":2,"cards":[{"id":"A","maxUses":1,"followups":[{"id":"B","maxUses":1},{"id":"C","maxUses":1}]}]}`,
+			wantFrom:   1,
+			wantReport: []string{"A: followup moved to followups"},
+		},
+		{
+			name:       "empty singular followup",
+			input:      `{"cards": [{"id": "A", "maxUses": 1, "noFollowup": []}]}`,
+			want:       `{"schemaVersion":2,"cards":[{"id":"A","maxUses":1}]}`,
+			wantFrom:   1,
+			wantReport: []string{"A: empty noFollowup removed"},
+		},
+		{
+			name:     "followup with maxUses 0",
+			input:    `{"cards": [{"id": "A", "maxUses": 1, "yesFollowup": {"id": "B", "maxUses": 0, "noFollowup": {"id": "C", "maxUses": 0}}}]}`,
+			want:     `{"schemaVersion":2,"cards":[{"id":"A","maxUses":1,"yesFollowups":[{"id":"B","maxUses":1,"noFollowups":[{"id":"C","maxUses":1}]}]}]}`,
+			wantFrom: 1,
+			wantReport: []string{
+				"A: yesFollowup moved to yesFollowups",
+				"B: noFollowup moved to noFollowups",
+				"B: maxUses 0 on a followup set to 1",
+				"C: maxUses 0 on a followup set to 1",
+			},
+		},
+		{
+			name:       "top-level card with maxUses 0 stays off",
+			input:      `{"cards": [{"id": "A", "maxUses": 0}]}`,
+			want:       `{"schemaVersion":2,"cards":[{"id":"A","maxUses":0}]}`,
+			wantFrom:   1,
+			wantReport: nil,
+		},
+		{
+			name:       "omitted maxUses",
+			input:      `{"cards": [{"id": "A"}, {"id": "B", "maxUses": 2}]}`,
+			want:       `{"schemaVersion":2,"cards":[{"id":"A"},{"id":"B","maxUses":2}]}`,
+			wantFrom:   1,
+			wantReport: []string{"A: omitted maxUses now means 1, version 1 never showed the card"},
+		},
+		{
+			name:       "card array",
+			input:      `[{"id": "A", "maxUses": 1}]`,
+			want:       `{"schemaVersion":2,"cards":[{"id":"A","maxUses":1}]}`,
+			wantFrom:   1,
+			wantReport: []string{"card array wrapped in a deck object"},
+		},
+		{
+			name:     "current version is left alone",
+			input:    `{"schemaVersion": 2, "cards": [{"id": "A", "yesFollowups": [{"id": "B", "maxUses": 0}]}]}`,
+			want:     `{"schemaVersion":2,"cards":[{"id":"A","yesFollowups":[{"id":"B","maxUses":0}]}]}`,
+			wantFrom: 2,
+		},
+		{
+			name:    "schemaVersion as a string",
+			input:   `{"schemaVersion": "2", "cards": []}`,
+			wantErr: "schemaVersion must be a positive whole number, got 2",
+		},
+		{
+			name:    "fractional schemaVersion",
+			input:   `{"schemaVersion": 1.5, "cards": []}`,
+			wantErr: "schemaVersion must be a positive whole number, got 1.5",
+		},
+		{
+			name:    "zero schemaVersion",
+			input:   `{"schemaVersion": 0, "cards": []}`,
+			wantErr: "schemaVersion must be a positive whole number, got 0",
+		},
+		{
+			name:    "newer schemaVersion",
+			input:   `{"schemaVersion": 3, "cards": []}`,
+			wantErr: "deck schema version 3 is newer than supported version 2",
+		},
+		{
+			name:    "not a deck",
+			input:   `"cards"`,
+			wantErr: "deck must be an object or an array of cards",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := decodeJSONDocument([]byte(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deck, from, report, err := upgradeDeckDocument(doc)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := json.Marshal(deck)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("upgraded deck\n%s\nwant\n%s", got, tt.want)
+			}
+			if from != tt.wantFrom {
+				t.Errorf("from = %d, want %d", from, tt.wantFrom)
+			}
+			if !slices.Equal(report, tt.wantReport) {
+				t.Errorf("report\n%s\nwant\n%s", strings.Join(report, "\n"), strings.Join(tt.wantReport, "\n"))
+			}
+		})
+	}
+}
+
+func TestDecodeDeckFileDefaults(t *testing.T) {
+	// A version 1 deck as the desktop client read it
+	data := `{"cards": [
+		{"id": "A", "yesFollowup": {"id": "B", "maxUses": 0}},
+		{"id": "C", "maxUses": 0, "noFollowup": {"id": "D", "delay": 0}}
+	]}`
+
+	var deck Deck
+	if _, _, _, err := decodeDeckFile("deck.json", []byte(data), &deck); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		card        *Card
+		wantID      string
+		wantMaxUses int
+		wantDelay   int
+	}{
+		{deck.Cards[0], "A", 1, 0},
+		{deck.Cards[0].YesFollowups[0], "B", 1, 1},
+		{deck.Cards[1], "C", 0, 0},
+		{deck.Cards[1].NoFollowups[0], "D", 1, 0},
+	}
+	for _, tt := range tests {
+		if tt.card.ID != tt.wantID || tt.card.MaxUses != tt.wantMaxUses || tt.card.Delay != tt.wantDelay {
+			t.Errorf("card %s: maxUses %d, delay %d, want %s with maxUses %d, delay %d",
+				tt.card.ID, tt.card.MaxUses, tt.card.Delay, tt.wantID, tt.wantMaxUses, tt.wantDelay)
+		}
+	}
+	if deck.SchemaVersion != deckSchemaVersion {
+		t.Errorf("schemaVersion %d, want %d", deck.SchemaVersion, deckSchemaVersion)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const endingsFile = "endings.json"
+
+// EndingRecord tracks when an ending was first reached and how often
+type EndingRecord struct {
+	FirstDay       int       `json:"firstDay"`
+	FirstRun       int       `json:"firstRun"` // Run number in the stats history
+	FirstReachedAt time.Time `json:"firstReachedAt"`
+	Count          int       `json:"count"`
+}
+
+// EndingCollection holds every ending the player has discovered
+type EndingCollection struct {
+	Endings map[string]*EndingRecord `json:"endings"`
+}
+
+func loadEndings() *EndingCollection {
+	collection := &EndingCollection{}
+	if err := loadUserJSON(profileFile(endingsFile), collection); err != nil {
+		log.Printf("Failed to load endings: %v", err)
+	}
+	if collection.Endings == nil {
+		collection.Endings = make(map[string]*EndingRecord)
+	}
+	return collection
+}
+
+// Discovered returns the number of known endings the player has reached
+func (c *EndingCollection) Discovered() int {
+	count := 0
+	for _, ending := range allEndings {
+		if c.Endings[ending] != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Reach counts an ending and saves the collection. It reports whether the
+// ending was reached for the first time.
+func (c *EndingCollection) Reach(ending string, day, run int, now time.Time) bool {
+	record := c.Endings[ending]
+	isNew := record == nil
+	if isNew {
+		record = &EndingRecord{FirstDay: day, FirstRun: run, FirstReachedAt: now}
+		c.Endings[ending] = record
+	}
+	record.Count++
+
+	if err := saveUserJSON(profileFile(endingsFile), c); err != nil {
+		log.Printf("Failed to save endings: %v", err)
+	}
+	return isNew
+}
+
+// recordEnding adds the ending of the run that just finished to the gallery
+func (g *Game) recordEnding() {
+	if g.endingID == "" {
+		return
+	}
+	g.newEnding = g.endings.Reach(g.endingID, g.resources.Day-1, len(g.stats.Runs), g.clock.Now())
+}
+
+// galleryGeometry lays out the gallery panel and one cell per ending
+func (g *Game) galleryGeometry() (panel Rect, cells []Rect) {
+	l := g.layout
+	s := l.Scale
+	const columns = 3
+	gap := 12 * s
+	cellHeight := 180 * s
+	rows := (len(allEndings) + columns - 1) / columns
+
+	width := min(560*s, l.Width-20*s)
+	height := 80*s + float64(rows)*(cellHeight+gap) + 10*s
+	panel = Rect{X: (l.Width - width) / 2, Y: (l.Height - height) / 2, Width: width, Height: height}
+
+	cellWidth := (width - gap*(columns+1)) / columns
+	for i := range allEndings {
+		col, row := i%columns, i/columns
+		cells = append(cells, Rect{
+			X:      panel.X + gap + float64(col)*(cellWidth+gap),
+			Y:      panel.Y + 80*s + float64(row)*(cellHeight+gap),
+			Width:  cellWidth,
+			Height: cellHeight,
+		})
+	}
+	return panel, cells
+}
+
+func (g *Game) drawGalleryScreen(screen *ebiten.Image) {
+	l := g.layout
+	s := l.Scale
+	panel, cells := g.galleryGeometry()
+
+	// Draw overlay and panel
+	drawOverlay(screen, l)
+	vector.DrawFilledRect(screen, float32(panel.X), float32(panel.Y), float32(panel.Width), float32(panel.Height), colorPanel, true)
+
+	// Title and progress
+	title := tr("gallery.title")
+	w, _ := getBoundsSize(boldFont, title)
+	drawTextWithOptions(screen, title, boldFont, int(panel.CenterX())-w/2, int(panel.Y+40*s), colorTextLight)
+
+	progress := fmt.Sprintf(tr("gallery.progress"), g.endings.Discovered(), len(allEndings))
+	w, _ = getBoundsSize(smallFont, progress)
+	drawTextWithOptions(screen, progress, smallFont, int(panel.CenterX())-w/2, int(panel.Y+62*s), colorTextLight)
+
+	padding := 10 * s
+	for i, ending := range allEndings {
+		cell := cells[i]
+		record := g.endings.Endings[ending]
+
+		// Locked endings are drawn as a dark silhouette with a question mark
+		if record == nil {
+			drawCardShape(screen, float32(cell.X), float32(cell.Y), float32(cell.Width), float32(cell.Height), s,
+				withOpacity(colorTextPrimary, 0.6), colorCardBorder)
+			w, h := getBoundsSize(boldFont, "?")
+			drawTextWithOptions(screen, "?", boldFont, int(cell.CenterX())-w/2, int(cell.CenterY())+h/2, colorTextLight)
+			continue
+		}
+
+		drawCardShape(screen, float32(cell.X), float32(cell.Y), float32(cell.Width), float32(cell.Height), s,
+			colorCard, colorCardBorder)
+		drawWrappedText(screen, tr("ending."+ending), smallFont,
+			int(cell.X+padding), int(cell.Y+padding+12*s), int(cell.Width-2*padding), colorTextPrimary)
+
+		// When and how often it was reached
+		details := fmt.Sprintf(tr("gallery.details"), record.FirstDay, record.FirstRun, record.Count)
+		drawWrappedText(screen, details, smallFont,
+			int(cell.X+padding), int(cell.Y+cell.Height-padding-18*s), int(cell.Width-2*padding), colorTextPrimary)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import "sort"
+
+// followupsFor returns the followup candidates for the answer given to a card
+func followupsFor(card *Card, isYes bool) []*Card {
+	if card.IsInfoOnly {
+		return card.Followups
+	}
+	if isYes {
+		return card.YesFollowups
+	}
+	return card.NoFollowups
+}
+
+// queueFollowup picks one of the candidates by probability and schedules it.
+// Candidates without probabilities are picked uniformly.
+func (g *Game) queueFollowup(candidates []*Card, parentCardID string, parentEntry int) {
+	if len(candidates) == 0 {
+		return
+	}
+
+	total := 0.0
+	for _, card := range candidates {
+		total += card.Probability
+	}
+
+	var selected *Card
+	if total > 0 {
+		roll := g.rng.Float64() * total
+		cumulative := 0.0
+		for _, card := range candidates {
+			cumulative += card.Probability
+			if roll < cumulative {
+				selected = card
+				break
+			}
+		}
+	}
+	if selected == nil {
+		selected = candidates[g.rng.Intn(len(candidates))]
+	}
+
+	selected.ParentCardID = parentCardID
+
+	g.delayedCards = append(g.delayedCards, FollowupCardItem{
+		Card:         selected,
+		ShowOnDay:    g.resources.Day + selected.Delay,
+		ParentCardID: parentCardID,
+		ParentEntry:  parentEntry,
+	})
+	sort.SliceStable(g.delayedCards, func(i, j int) bool {
+		return g.delayedCards[i].ShowOnDay < g.delayedCards[j].ShowOnDay
+	})
+}
+
+// nextFollowup takes the first queued followup that is due, meets its
+// requirements and has uses left. Followups that aren't shown stay queued.
+func (g *Game) nextFollowup() *Card {
+	for i, delayedCard := range g.delayedCards {
+		if delayedCard.ShowOnDay > g.resources.Day {
+			continue
+		}
+		if !g.checkRequirements(delayedCard.Card.Requirements) || delayedCard.Card.Uses >= delayedCard.Card.MaxUses {
+			continue
+		}
+
+		// Remove from delayed cards
+		g.delayedCards = append(g.delayedCards[:i], g.delayedCards[i+1:]...)
+		delayedCard.Card.Uses++
+		g.currentCause = delayedCard.ParentEntry
+		return delayedCard.Card
+	}
+	return nil
+}
+
+// forEachCard calls fn for every card in the deck, followups included
+func forEachCard(cards []*Card, fn func(card *Card)) {
+	for _, card := range cards {
+		fn(card)
+		forEachCard(card.YesFollowups, fn)
+		forEachCard(card.NoFollowups, fn)
+		forEachCard(card.Followups, fn)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+)
+
+// Font sizes at a layout scale of 1
+const (
+	regularFontSize = 16
+	boldFontSize    = 20
+	smallFontSize   = 12
+	emojiFontSize   = 24
+
+	// Faces are rebuilt only when the scale moves by more than this step
+	fontScaleStep = 0.05
+)
+
+var (
+	// Parsed font files the faces are created from
+	textFontSource  *opentype.Font
+	emojiFontSource *opentype.Font
+
+	// Files the sources were parsed from
+	loadedFonts ThemeFonts
+
+	// Scale the current faces were created at
+	fontScale float64
+)
+
+// Text font built into the binary, used when a theme's font can't be loaded
+const defaultFontFile = "font.ttf"
+
+// loadFonts parses the font files. The text font is required and falls
+// back to the built-in font, the emoji font is optional and only logged
+// when missing.
+func loadFonts(fonts ThemeFonts) error {
+	source, err := parseFont(assets, fonts.Text)
+	if err != nil {
+		reportAssetError(err)
+		if source, err = parseFont(embeddedDefaults(), defaultFontFile); err != nil {
+			return err
+		}
+	}
+	textFontSource = source
+
+	loadedFonts = fonts
+	emojiFontSource = nil
+	if fonts.Emoji == "" {
+		return nil
+	}
+
+	emojiFontSource, err = parseFont(assets, fonts.Emoji)
+	if err != nil {
+		// Fall back to standard font
+		log.Printf("Failed to load emoji font: %v", err)
+	}
+
+	return nil
+}
+
+func parseFont(fsys fs.FS, name string) (*opentype.Font, error) {
+	data, err := fs.ReadFile(fsys, assetName(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load font: %w", err)
+	}
+
+	source, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font %s: %w", name, err)
+	}
+	return source, nil
+}
+
+// setFontScale rebuilds the font faces for the given scale, which combines
+// the layout scale and the monitor's device scale factor
+func setFontScale(scale float64) error {
+	scale = math.Max(math.Round(scale/fontScaleStep)*fontScaleStep, fontScaleStep)
+	if scale == fontScale {
+		return nil
+	}
+
+	regular, err := newFace(textFontSource, regularFontSize*scale)
+	if err != nil {
+		return fmt.Errorf("failed to create regular font face: %w", err)
+	}
+
+	bold, err := newFace(textFontSource, boldFontSize*scale)
+	if err != nil {
+		return fmt.Errorf("failed to create bold font face: %w", err)
+	}
+
+	small, err := newFace(textFontSource, smallFontSize*scale)
+	if err != nil {
+		return fmt.Errorf("failed to create small font face: %w", err)
+	}
+
+	regularFont, boldFont, smallFont = regular, bold, small
+	fontScale = scale
+
+	// Create emoji font face
+	if emojiFontSource != nil {
+		emojiFace, err = newFace(emojiFontSource, emojiFontSize*scale)
+		if err != nil {
+			log.Printf("Failed to create emoji font face: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func newFace(source *opentype.Font, size float64) (font.Face, error) {
+	const dpi = 72
+	return opentype.NewFace(source, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     dpi,
+		Hinting: font.HintingFull,
+	})
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
-	"image/color"
+	"fmt"
 	"log"
 	"math"
+	"math/rand"
+	"path"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -15,13 +17,16 @@ type Game struct {
 	state          int
 	resources      Resources
 	cards          []*Card
+	characters     map[string]*Character
+	images         *imageCache
 	availableCards []*Card
 	currentCard    *Card
 	delayedCards   []FollowupCardItem
 	playedCardIDs  []string
 	gameOver       bool
+	gameWon        bool
 	winCardShown   bool
-	gameOverReason string
+	endingID       string
 
 	// Card animation
 	dragging           bool
@@ -33,67 +38,149 @@ type Game struct {
 	cardTargetRotation float64
 	cardTargetOpacity  float64
 	animating          bool
+	swipeYes           bool    // Answer of the card being swiped away
+	swipeElapsed       float64 // Ticks of the swipe so far, scaled by the animation speed
 
-	// Stack animation
-	stackItems [2]struct {
-		X, Y    float64
-		Opacity float64
-	}
+	// Stack animation, 0 at rest and 1 once every stack card has moved
+	// one place forward
+	stackProgress float64
+	stackCardImg  *ebiten.Image
+	stackTheme    *Theme
+
+	// Themes
+	themes     []*Theme
+	themeIndex int
 
 	// UI elements
-	restartButton Button
-	aboutButton   Button
+	layout         Layout
+	restartButton  Button
+	aboutButton    Button
+	soundButton    Button
+	settingsButton Button
+	undoButton     Button
+
+	// Player preferences
+	settings Settings
+
+	// Sound
+	audio    *AudioManager
+	deckDir  string
+	deckFile string
+
+	// Run identity and lifetime statistics
+	clock           Clock
+	rng             *rand.Rand
+	rngSource       *gameSource
+	mode            string
+	seed            int64
+	deckHash        string
+	stats           *StatsStore
+	newPersonalBest bool
+
+	// Endings the player has discovered
+	endings   *EndingCollection
+	newEnding bool
+
+	// Deck achievements and their unlock notifications
+	achievements []*Achievement
+	unlocks      *AchievementStore
+	toasts       []string
+	toastTicks   int
+
+	// Decisions made in the current run
+	journal       []JournalEntry
+	currentCause  int // Journal entry that queued the current card, -1 if none
+	journalScroll float64
+
+	// Daily challenge attempts and the date of the one being played
+	daily     *DailyHistory
+	dailyDate string
+
+	// Casual mode snapshots, one per decision
+	undoStack []engineSnapshot
+	undoCount int
+
+	// Developer console, and whether it or a deck reload changed the
+	// current run, which leaves it out of the records
+	console  devConsole
+	debugRun bool
+
+	// Deck file polling for hot reload, and why the deck failed to load.
+	// The watched files are the deck files and globbed include directories.
+	deckWatch    []string
+	deckModTime  time.Time
+	deckPollTick int
+	deckError    error
+
+	// Deck packs in the content directory and the report of the last merge
+	packs        []*Pack
+	packReport   []string
+	packsChanged bool
+
+	// Players on this machine and the leaderboard built from their runs
+	profiles       *ProfileList
+	newProfileName []rune // Name being typed, nil when not adding a profile
+	profilesScroll float64
+	leaderboards   []leaderboardBoard
+	leaderboardTab int
+
+	// Replay of the current run, and the player when watching one
+	difficulty string
+	recording  Replay
+	replayFile string
+	player     *replayPlayer
 }
 
 func NewGame() *Game {
+	profiles := loadProfiles()
+	settings := loadSettings()
+	currentLanguage = settings.Language
+
 	g := &Game{
-		state: stateGame,
-		resources: Resources{
-			Motivation:  40,
-			Performance: 40,
-			Colleagues:  40,
-			Boss:        40,
-			Day:         1,
-		},
+		state:        stateGame,
+		resources:    launchStart,
 		cardX:        0,
 		cardY:        0,
 		cardOpacity:  1.0,
 		cardRotation: 0,
 
-		// Setup stack items
-		stackItems: [2]struct {
-			X, Y    float64
-			Opacity float64
-		}{
-			{X: 8, Y: 4, Opacity: 0.8},
-			{X: 4, Y: 2, Opacity: 0.9},
-		},
-
 		// Setup buttons
 		restartButton: Button{
-			X:          screenWidth/2 - 80,
-			Y:          screenHeight/2 + 50,
-			Width:      160,
-			Height:     50,
-			Text:       "Yeniden Başla",
-			Color:      colorRestartBtn,
-			HoverColor: color.RGBA{29, 78, 216, 255},
-			TextColor:  colorTextLight,
+			Text: tr("restart"),
 		},
 		aboutButton: Button{
-			X:          screenWidth - 45,
-			Y:          15,
-			Width:      30,
-			Height:     30,
-			Text:       "i",
-			Color:      colorCardBorder,
-			HoverColor: color.RGBA{75, 85, 101, 255},
-			TextColor:  colorTextLight,
+			Text: "i",
+		},
+		soundButton: Button{
+			Text: "♪",
+		},
+		settingsButton: Button{
+			Text: "≡",
 		},
+		clock:      systemClock{},
+		profiles:   profiles,
+		daily:      loadDailyHistory(),
+		difficulty: difficultyNormal,
+		settings:   settings,
+		audio:      newAudioManager("sounds", settings.Audio),
+		stats:      loadStats(),
+		endings:    loadEndings(),
+		unlocks:    loadAchievements(),
 	}
+	g.setLayout(computeLayout(screenWidth, screenHeight))
+
+	// Load themes
+	themes, err := loadThemes("themes.json")
+	if err != nil {
+		reportAssetError(fmt.Errorf("themes: %w", err))
+	}
+	g.themes = themes
+	g.applySettings()
 
 	// Load cards
-	if err := g.loadCards("assets/deck.json"); err != nil {
+	g.mode = g.standardMode()
+	g.startRun(g.clock.Now().UnixNano())
+	if err := g.loadCards(findDeckFile(assets, ".", defaultDeckName)); err != nil {
 		log.Printf("Failed to load cards: %v", err)
 		g.showWelcomeCard() // Show welcome card even if deck fails to load
 	} else {
@@ -104,9 +191,10 @@ func NewGame() *Game {
 }
 
 func (g *Game) showWelcomeCard() {
+	g.currentCause = -1
 	welcomeCard := &Card{
-		ID:         "WELCOME",
-		Text:       "Hazırsanız başlayalım",
+		ID:         welcomeCardID,
+		Text:       tr("welcome"),
 		IsInfoOnly: true,
 		Effects:    Effects{},
 		MaxUses:    1,
@@ -120,6 +208,8 @@ func (g *Game) showWelcomeCard() {
 }
 
 func (g *Game) getNextCard() *Card {
+	g.currentCause = -1
+
 	// Check if there are no available cards, reshuffle
 	if len(g.availableCards) == 0 {
 		g.resetAvailableCards()
@@ -127,16 +217,16 @@ func (g *Game) getNextCard() *Card {
 	}
 
 	// Check for win condition card
-	if g.resources.Day >= 70 &&
-		g.resources.Motivation >= 70 &&
-		g.resources.Performance >= 70 &&
-		g.resources.Colleagues >= 70 &&
-		g.resources.Boss >= 70 &&
+	if g.resources.Day >= winDay &&
+		g.resources.Motivation >= winStat &&
+		g.resources.Performance >= winStat &&
+		g.resources.Colleagues >= winStat &&
+		g.resources.Boss >= winStat &&
 		!g.winCardShown {
 
 		// Find the competitor job offer card
 		for i, card := range g.availableCards {
-			if card.ID == "COMPETITOR_JOB_OFFER" && card.Uses < card.MaxUses {
+			if card.ID == competitorOfferCardID && card.Uses < card.MaxUses {
 				g.winCardShown = true
 				selectedCard := card
 				// Remove from available pool
@@ -148,17 +238,8 @@ func (g *Game) getNextCard() *Card {
 	}
 
 	// Check delayed cards first
-	for i, delayedCard := range g.delayedCards {
-		if delayedCard.ShowOnDay <= g.resources.Day {
-			if g.checkRequirements(delayedCard.Card.Requirements) {
-				if delayedCard.Card.Uses < delayedCard.Card.MaxUses {
-					// Remove from delayed cards
-					g.delayedCards = append(g.delayedCards[:i], g.delayedCards[i+1:]...)
-					delayedCard.Card.Uses++
-					return delayedCard.Card
-				}
-			}
-		}
+	if followup := g.nextFollowup(); followup != nil {
+		return followup
 	}
 
 	// Filter cards
@@ -197,7 +278,7 @@ func (g *Game) getNextCard() *Card {
 	}
 
 	// Select a random card from valid cards
-	randomIndex := random.Intn(len(validCards))
+	randomIndex := g.rng.Intn(len(validCards))
 	selectedCard := validCards[randomIndex]
 
 	// Remove from available cards
@@ -280,24 +361,85 @@ func (g *Game) checkRequirements(req *Requirement) bool {
 }
 
 func (g *Game) updateResources(effects Effects) {
-	// Apply effects with scaling
-	motivationChange := float64(effects.Motivation) * 0.5
-	performanceChange := float64(effects.Performance) * 0.5
-	colleaguesChange := float64(effects.Colleagues) * 0.35
-	bossChange := float64(effects.Boss) * 0.5
+	before := g.resources
 
 	// Update resources with clamping
-	g.resources.Motivation = clamp(g.resources.Motivation+int(motivationChange), minValue, maxValue)
-	g.resources.Performance = clamp(g.resources.Performance+int(performanceChange), minValue, maxValue)
-	g.resources.Colleagues = clamp(g.resources.Colleagues+int(colleaguesChange), minValue, maxValue)
-	g.resources.Boss = clamp(g.resources.Boss+int(bossChange), minValue, maxValue)
+	changes := statChanges(effects)
+	g.resources.Motivation = clamp(g.resources.Motivation+changes[0], minValue, maxValue)
+	g.resources.Performance = clamp(g.resources.Performance+changes[1], minValue, maxValue)
+	g.resources.Colleagues = clamp(g.resources.Colleagues+changes[2], minValue, maxValue)
+	g.resources.Boss = clamp(g.resources.Boss+changes[3], minValue, maxValue)
 
 	g.resources.Day++
 
+	// Play stat sounds, danger takes priority over a plain change
+	if enteredDanger(before, g.resources) {
+		g.audio.play(soundDanger)
+	} else if statsChanged(before, g.resources) {
+		g.audio.play(soundStatChange)
+	}
+
 	// Check for game over conditions
 	g.checkGameOver()
 }
 
+// Scaling of card effects per stat, in display order
+var effectScales = [4]float64{0.5, 0.5, 0.35, 0.5}
+
+// statChanges returns how much effects move each stat, in display order
+func statChanges(effects Effects) [4]int {
+	changes := [4]int{effects.Motivation, effects.Performance, effects.Colleagues, effects.Boss}
+	for i := range changes {
+		changes[i] = int(float64(changes[i]) * effectScales[i])
+	}
+	return changes
+}
+
+// statValues returns the four stats in display order
+func statValues(r Resources) [4]int {
+	return [4]int{r.Motivation, r.Performance, r.Colleagues, r.Boss}
+}
+
+// statsChanged reports whether any stat differs between before and after
+func statsChanged(before, after Resources) bool {
+	return statValues(before) != statValues(after)
+}
+
+// enteredDanger reports whether any stat crossed into the danger zone
+func enteredDanger(before, after Resources) bool {
+	inDanger := func(v int) bool { return v <= dangerLow || v >= dangerHigh }
+
+	prev := statValues(before)
+	for i, v := range statValues(after) {
+		if inDanger(v) && !inDanger(prev[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Endings a run can reach, the game over text is "ending.<id>"
+const (
+	endingMotivationLow   = "motivationLow"
+	endingMotivationHigh  = "motivationHigh"
+	endingPerformanceLow  = "performanceLow"
+	endingPerformanceHigh = "performanceHigh"
+	endingColleaguesLow   = "colleaguesLow"
+	endingColleaguesHigh  = "colleaguesHigh"
+	endingBossLow         = "bossLow"
+	endingBossHigh        = "bossHigh"
+	endingCompetitorOffer = "competitorOffer"
+)
+
+// allEndings lists every ending in display order
+var allEndings = []string{
+	endingMotivationLow, endingMotivationHigh,
+	endingPerformanceLow, endingPerformanceHigh,
+	endingColleaguesLow, endingColleaguesHigh,
+	endingBossLow, endingBossHigh,
+	endingCompetitorOffer,
+}
+
 func (g *Game) checkGameOver() bool {
 	if g.gameOver {
 		return true
@@ -315,25 +457,28 @@ func (g *Game) checkGameOver() bool {
 		g.gameOver = true
 		g.state = stateGameOver
 
-		// Set game over reason
+		// Set the ending reached
 		if g.resources.Motivation <= minValue {
-			g.gameOverReason = "Motivasyonunuz tükendi. İşi bıraktınız."
+			g.endingID = endingMotivationLow
 		} else if g.resources.Motivation >= maxValue {
-			g.gameOverReason = "Aşırı motivasyon sizi tüketti. Burnout oldunuz."
+			g.endingID = endingMotivationHigh
 		} else if g.resources.Performance <= minValue {
-			g.gameOverReason = "Performansınız çok düşük. Kovuldunuz."
+			g.endingID = endingPerformanceLow
 		} else if g.resources.Performance >= maxValue {
-			g.gameOverReason = "Çok fazla çalıştınız. Tükenmişlik sendromu yaşadınız."
+			g.endingID = endingPerformanceHigh
 		} else if g.resources.Colleagues <= minValue {
-			g.gameOverReason = "İş arkadaşlarınız sizden nefret ediyor. Yalnız kaldınız ve istifa ettiniz."
+			g.endingID = endingColleaguesLow
 		} else if g.resources.Colleagues >= maxValue {
-			g.gameOverReason = "İş arkadaşlarınızla çok yakınsınız. Bu aranızdaki sosyalliğin artmasına ve iş yerine sosyal kulüp muamelesi yapmanıza sebep oldu. Kovuldunuz."
+			g.endingID = endingColleaguesHigh
 		} else if g.resources.Boss <= minValue {
-			g.gameOverReason = "Patronunuz sizi sevmiyor. Kovuldunuz."
+			g.endingID = endingBossLow
 		} else if g.resources.Boss >= maxValue {
-			g.gameOverReason = "Patronunuz sizi çok seviyor. Terfi ettiniz ve oyunu kazandınız!"
+			g.endingID = endingBossHigh
+			g.gameWon = true
 		}
 
+		g.onGameOver()
+
 		return true
 	}
 	return false
@@ -345,19 +490,28 @@ func (g *Game) processCard(isYes bool) {
 		return
 	}
 
+	// Achievements see the state after the card has been resolved
+	defer g.checkAchievements()
+	g.takeSnapshot()
+	g.recordStep(isYes)
+
 	// Record card ID
 	if g.currentCard.ID != "" {
 		g.playedCardIDs = append(g.playedCardIDs, g.currentCard.ID)
 	}
 
-	// Define the win message for the competitor offer scenario
-	const competitorWinMessage = "Rakip firmadan gelen teklifi kabul ettiniz ve yeni bir başlangıç yaptınız. Oyunu kazandınız!"
+	// Record the answer, the stat changes are filled in below
+	entry := g.newJournalEntry(isYes)
+	before := g.resources
 
 	// Special case for competitor job offer
-	if g.currentCard.ID == "COMPETITOR_JOB_OFFER" && isYes {
+	if g.currentCard.ID == competitorOfferCardID && isYes {
+		g.addJournalEntry(entry)
 		g.gameOver = true
 		g.state = stateGameOver
-		g.gameOverReason = competitorWinMessage
+		g.endingID = endingCompetitorOffer
+		g.gameWon = true
+		g.onGameOver()
 		return
 	}
 
@@ -373,18 +527,52 @@ func (g *Game) processCard(isYes bool) {
 			g.updateResources(g.currentCard.NoEffects)
 		}
 	}
+	entry.Deltas = resourceDeltas(before, g.resources)
+	entryIndex := g.addJournalEntry(entry)
 
-	// If game is not over, get next card
+	// If game is not over, queue followups and get next card
 	if !g.gameOver {
+		g.queueFollowup(followupsFor(g.currentCard, isYes), g.currentCard.ID, entryIndex)
+
 		nextCard := g.getNextCard()
 		g.currentCard = nextCard
 		g.cardX = 0
 		g.cardY = 0
 		g.cardOpacity = 1.0
 		g.cardRotation = 0
+		g.playCardSound()
+	}
+}
+
+// onGameOver runs once when a run ends
+func (g *Game) onGameOver() {
+	// Watching a replay or a run changed while debugging doesn't count
+	if g.player == nil && !g.debugRun {
+		g.saveReplay()
+		g.recordRun()
+		g.recordEnding()
+		g.recordDaily()
+	}
+
+	if g.gameWon {
+		g.audio.play(soundWin)
+	} else {
+		g.audio.play(soundLoss)
 	}
 }
 
+// playCardSound plays the sound for the card that just appeared
+func (g *Game) playCardSound() {
+	if g.currentCard == nil {
+		return
+	}
+	if g.currentCard.Sound != "" {
+		g.audio.playFile(path.Join(g.deckDir, g.currentCard.Sound))
+		return
+	}
+	g.audio.play(soundCardAppear)
+}
+
 // Animation functions
 func (g *Game) animateCardAway(isYes bool) {
 	g.animating = true
@@ -393,39 +581,91 @@ func (g *Game) animateCardAway(isYes bool) {
 		direction = -1.0
 	}
 
-	g.cardTargetX = direction * float64(screenWidth/1.5)
+	g.cardTargetX = direction * g.layout.Width / 1.5
 	g.cardTargetRotation = direction * 30
 	g.cardTargetOpacity = 0
 
-	// After animation completes, process the card
-	time.AfterFunc(500*time.Millisecond, func() {
-		g.processCard(isYes)
-		g.animating = false
+	// With reduced motion the card fades where it is
+	if g.settings.ReducedMotion {
+		g.cardTargetX = g.cardX
+		g.cardTargetRotation = 0
+	}
+	g.audio.play(soundSwipe)
 
-		// Reset card position for next card
-		g.cardX = 0
-		g.cardY = 0
-		g.cardRotation = 0
-		g.cardOpacity = 1.0
-	})
+	// Update processes the card once the animation completes
+	g.swipeYes = isYes
+	g.swipeElapsed = 0
 }
 
-func (g *Game) restartGame() {
-	g.resources = Resources{
-		Motivation:  50,
-		Performance: 50,
-		Colleagues:  50,
-		Boss:        50,
-		Day:         1,
+// finishSwipe answers the card that was swiped away and brings in the next
+func (g *Game) finishSwipe() {
+	g.processCard(g.swipeYes)
+	g.animating = false
+	g.stackProgress = 0
+
+	// Reset card position for next card
+	g.cardX = 0
+	g.cardY = 0
+	g.cardRotation = 0
+	g.cardOpacity = 1.0
+}
+
+// returnToGame closes an overlay and goes back to the game or game over screen
+func (g *Game) returnToGame() {
+	if g.gameOver {
+		g.state = stateGameOver
+	} else {
+		g.state = stateGame
 	}
+}
+
+// startRun seeds the card shuffle for a new run and starts recording it
+func (g *Game) startRun(seed int64) {
+	g.seed = seed
+	g.rngSource = newGameSource(seed)
+	g.rng = rand.New(g.rngSource)
+	g.undoStack = nil
+	g.undoCount = 0
+	g.debugRun = false
+	g.recording = Replay{Version: replayVersion, Seed: seed, Difficulty: g.difficulty, Mode: g.mode, Start: g.resources}
+	g.replayFile = ""
+}
+
+// Stats a run starts from. The run the game opens with starts lower.
+var (
+	runStart    = Resources{Motivation: 50, Performance: 50, Colleagues: 50, Boss: 50, Day: 1}
+	launchStart = Resources{Motivation: 40, Performance: 40, Colleagues: 40, Boss: 40, Day: 1}
+)
+
+func (g *Game) restartGame() {
+	g.player = nil
+	g.mode = g.standardMode()
+	g.resetRun(g.clock.Now().UnixNano(), runStart)
+}
+
+// resetRun starts a new run from the given stats
+func (g *Game) resetRun(seed int64, start Resources) {
+	g.resources = start
+	g.startRun(seed)
 
 	g.resetAvailableCards()
 	g.delayedCards = nil
 	g.playedCardIDs = nil
+	g.journal = nil
+	g.currentCause = -1
+	g.journalScroll = 0
 	g.gameOver = false
+	g.gameWon = false
+	g.newPersonalBest = false
+	g.newEnding = false
 	g.winCardShown = false
 	g.state = stateGame
 
+	// A card swiped away in the old run is never answered
+	g.animating = false
+	g.dragging = false
+	g.stackProgress = 0
+
 	g.showWelcomeCard()
 }
 
@@ -433,47 +673,118 @@ func (g *Game) restartGame() {
 func (g *Game) checkButtonHover(x, y int) {
 	// Check restart button hover
 	if g.state == stateGameOver {
-		g.restartButton.IsHovered = float64(x) >= g.restartButton.X &&
-			float64(x) <= g.restartButton.X+g.restartButton.Width &&
-			float64(y) >= g.restartButton.Y &&
-			float64(y) <= g.restartButton.Y+g.restartButton.Height
+		g.restartButton.IsHovered = g.restartButton.Contains(x, y)
 	}
 
-	// Check about button hover
-	g.aboutButton.IsHovered = float64(x) >= g.aboutButton.X &&
-		float64(x) <= g.aboutButton.X+g.aboutButton.Width &&
-		float64(y) >= g.aboutButton.Y &&
-		float64(y) <= g.aboutButton.Y+g.aboutButton.Height
+	// Check about and sound button hover
+	g.aboutButton.IsHovered = g.aboutButton.Contains(x, y)
+	g.soundButton.IsHovered = g.soundButton.Contains(x, y)
+	g.settingsButton.IsHovered = g.settingsButton.Contains(x, y)
+	g.undoButton.IsHovered = g.mode == modeCasual && g.undoButton.Contains(x, y)
+}
+
+// standardMode is the mode of a normal new run, casual if the player
+// turned it on
+func (g *Game) standardMode() string {
+	if g.settings.Casual {
+		return modeCasual
+	}
+	return modeStandard
 }
 
 func (g *Game) Update() error {
 	// Get mouse position
 	mx, my := ebiten.CursorPosition()
 	g.checkButtonHover(mx, my)
+	g.updateToasts()
+	g.updateDeckWatch()
+	if g.updateLoadErrors() {
+		return nil
+	}
+
+	// Developer console takes the keyboard while open
+	if inpututil.IsKeyJustPressed(ebiten.KeyF12) || inpututil.IsKeyJustPressed(ebiten.KeyBackquote) {
+		g.toggleConsole()
+	} else if g.console.open {
+		g.updateConsole()
+	}
+
+	// Menu screens take over input while open
+	switch g.state {
+	case stateSettings:
+		g.updateSettings()
+		return nil
+	case stateMenu:
+		g.updateMenu()
+		return nil
+	case stateStats, stateGallery, stateAchievements:
+		g.updateInfoScreen()
+		return nil
+	case stateJournal:
+		g.updateJournal()
+		return nil
+	case stateDaily:
+		g.updateDaily()
+		return nil
+	case stateProfiles:
+		g.updateProfiles()
+		return nil
+	case stateLeaderboard:
+		g.updateLeaderboard()
+		return nil
+	case statePacks:
+		g.updatePacks()
+		return nil
+	}
+
+	if !g.console.open {
+		// Switch theme
+		if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+			g.cycleTheme()
+		}
+
+		// Undo with Backspace or Ctrl+Z
+		if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) ||
+			(ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyZ)) {
+			g.undo()
+		}
+	}
 
 	// Check button clicks
 	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		// Sound button
+		if g.soundButton.IsHovered {
+			g.settings.Audio.Muted = !g.settings.Audio.Muted
+			g.audio.SetSettings(g.settings.Audio)
+			g.saveSettings()
+			return nil
+		}
+
+		// Undo button
+		if g.undoButton.IsHovered {
+			g.undo()
+			return nil
+		}
+
+		// Menu button
+		if g.settingsButton.IsHovered {
+			g.state = stateMenu
+			return nil
+		}
+
 		// About button
 		if g.aboutButton.IsHovered {
 			if g.state != stateAbout {
 				g.state = stateAbout
 			} else {
-				if g.gameOver {
-					g.state = stateGameOver
-				} else {
-					g.state = stateGame
-				}
+				g.returnToGame()
 			}
 			return nil
 		}
 
 		// Close about modal by clicking anywhere if it's open
 		if g.state == stateAbout {
-			if g.gameOver {
-				g.state = stateGameOver
-			} else {
-				g.state = stateGame
-			}
+			g.returnToGame()
 			return nil
 		}
 
@@ -484,18 +795,14 @@ func (g *Game) Update() error {
 		}
 	}
 
+	// A replay plays the cards instead of the player
+	g.updateReplay()
+
 	// Handle card dragging
-	if g.state == stateGame && !g.gameOver && !g.animating {
+	if g.state == stateGame && !g.gameOver && !g.animating && g.player == nil {
 		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-			// Get card bounds (centered in the screen)
-			cardWidth := 400.0
-			cardHeight := 500.0
-			cardX := (screenWidth - cardWidth) / 2
-			cardY := (screenHeight - cardHeight) / 2
-
 			// Check if click is on the card
-			if float64(mx) >= cardX && float64(mx) <= cardX+cardWidth &&
-				float64(my) >= cardY && float64(my) <= cardY+cardHeight {
+			if g.layout.Card.Contains(float64(mx), float64(my)) {
 				g.dragging = true
 				g.startX = float64(mx)
 			}
@@ -508,12 +815,14 @@ func (g *Game) Update() error {
 
 				// Update card position and rotation
 				g.cardX = deltaX
-				maxRotation := 15.0
-				g.cardRotation = math.Min(math.Max(deltaX/10, -maxRotation), maxRotation)
+				if !g.settings.ReducedMotion {
+					maxRotation := 15.0
+					g.cardRotation = math.Min(math.Max(deltaX/(10*g.layout.Scale), -maxRotation), maxRotation)
+				}
 			} else {
 				// Mouse released, check if swipe threshold reached
 				g.dragging = false
-				if math.Abs(g.currentX) > swipeThreshold {
+				if math.Abs(g.currentX) > g.swipeDistance() {
 					isYes := g.currentX > 0
 					g.animateCardAway(isYes)
 				} else {
@@ -530,10 +839,22 @@ func (g *Game) Update() error {
 	// Handle card animation
 	if g.animating {
 		// Animate card moving away
-		animSpeed := 0.1
+		animSpeed := g.animSpeed()
 		g.cardX += (g.cardTargetX - g.cardX) * animSpeed
 		g.cardRotation += (g.cardTargetRotation - g.cardRotation) * animSpeed
 		g.cardOpacity += (g.cardTargetOpacity - g.cardOpacity) * animSpeed
+
+		// Slide the stack forward behind it
+		if g.settings.ReducedMotion {
+			g.stackProgress = 1
+		} else {
+			g.stackProgress += (1 - g.stackProgress) * animSpeed
+		}
+
+		g.swipeElapsed += g.animationSpeed()
+		if g.swipeElapsed >= swipeDuration {
+			g.finishSwipe()
+		}
 	}
 
 	return nil
@@ -552,9 +873,58 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	case stateAbout:
 		g.drawGameScreen(screen)
 		g.drawAboutScreen(screen)
+	case stateSettings:
+		g.drawGameScreen(screen)
+		g.drawSettingsScreen(screen)
+	case stateMenu:
+		g.drawGameScreen(screen)
+		g.drawMenuScreen(screen)
+	case stateStats:
+		g.drawGameScreen(screen)
+		g.drawStatsScreen(screen)
+	case stateGallery:
+		g.drawGameScreen(screen)
+		g.drawGalleryScreen(screen)
+	case stateAchievements:
+		g.drawGameScreen(screen)
+		g.drawAchievementsScreen(screen)
+	case stateJournal:
+		g.drawGameScreen(screen)
+		g.drawJournalScreen(screen)
+	case stateDaily:
+		g.drawGameScreen(screen)
+		g.drawDailyScreen(screen)
+	case stateProfiles:
+		g.drawGameScreen(screen)
+		g.drawProfilesScreen(screen)
+	case stateLeaderboard:
+		g.drawGameScreen(screen)
+		g.drawLeaderboardScreen(screen)
+	case statePacks:
+		g.drawGameScreen(screen)
+		g.drawPacksScreen(screen)
 	}
+	g.drawReplayHUD(screen)
+	g.drawLoadErrors(screen)
+	g.drawConsole(screen)
+
+	// Unlock notifications show over every screen
+	g.drawToast(screen)
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return screenWidth, screenHeight
+	// Render at native resolution. The device scale factor is read every
+	// frame so moving the window to another monitor is picked up.
+	deviceScale := ebiten.Monitor().DeviceScaleFactor()
+	width := int(math.Ceil(float64(outsideWidth) * deviceScale))
+	height := int(math.Ceil(float64(outsideHeight) * deviceScale))
+
+	// Recompute geometry only when the framebuffer size changes
+	if float64(width) != g.layout.Width || float64(height) != g.layout.Height {
+		g.setLayout(computeLayout(float64(width), float64(height)))
+		if err := setFontScale(g.fontScale()); err != nil {
+			log.Printf("Failed to rescale fonts: %v", err)
+		}
+	}
+	return width, height
 }
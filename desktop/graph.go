@@ -0,0 +1,515 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Symbols of the requirement comparisons in graphs and reports
+var comparisonSymbols = map[string]string{
+	"gt":  ">",
+	"lt":  "<",
+	"gte": ">=",
+	"lte": "<=",
+	"eq":  "=",
+}
+
+// Writers of the story graph formats
+var graphFormats = map[string]func(g *storyGraph) []byte{
+	"dot":     (*storyGraph).dot,
+	"mermaid": (*storyGraph).mermaid,
+	"svg":     (*storyGraph).svg,
+}
+
+// Formats picked by the extension of the output file
+var graphExtensions = map[string]string{
+	".dot":     "dot",
+	".gv":      "dot",
+	".mmd":     "mermaid",
+	".mermaid": "mermaid",
+	".svg":     "svg",
+}
+
+// storyGraph is the cards of a deck and the followups between them
+type storyGraph struct {
+	nodes []*storyNode
+	edges []*storyEdge
+}
+
+type storyNode struct {
+	id   string // Unique node name in the output
+	card *Card
+	top  bool // Shuffled into runs, not only shown as a followup
+}
+
+type storyEdge struct {
+	from, to *storyNode
+	answer   string  // "yes", "no", or empty for info cards
+	delay    int     // Days until the followup is shown
+	chance   float64 // Chance of being picked among the other candidates
+	choices  int     // Number of candidates it was picked from
+}
+
+// buildStoryGraph makes a node of every card. Cards named by refs are one
+// node however often they are named; inline followups are a node each,
+// since different parents reuse IDs for different cards.
+func buildStoryGraph(deck *Deck) *storyGraph {
+	g := &storyGraph{}
+	byID := make(map[string]*storyNode)
+	byCard := make(map[*Card]*storyNode)
+
+	var add func(card *Card, defined bool) *storyNode
+	add = func(card *Card, defined bool) *storyNode {
+		if defined || card.fromRef {
+			if node := byID[card.ID]; node != nil {
+				return node
+			}
+		} else if node := byCard[card]; node != nil {
+			return node
+		}
+
+		node := &storyNode{id: fmt.Sprintf("n%d", len(g.nodes)+1), card: card}
+		g.nodes = append(g.nodes, node)
+		if defined || card.fromRef {
+			byID[card.ID] = node
+		} else {
+			byCard[card] = node
+		}
+
+		lists := []struct {
+			answer string
+			cards  []*Card
+		}{
+			{choiceYes, card.YesFollowups},
+			{choiceNo, card.NoFollowups},
+			{"", card.Followups},
+		}
+		for _, list := range lists {
+			total := 0.0
+			for _, followup := range list.cards {
+				total += followup.Probability
+			}
+			for _, followup := range list.cards {
+				// Same odds as queueFollowup
+				chance := 1 / float64(len(list.cards))
+				if total > 0 {
+					chance = followup.Probability / total
+				}
+				edge := &storyEdge{
+					from:    node,
+					answer:  list.answer,
+					delay:   followup.Delay,
+					chance:  chance,
+					choices: len(list.cards),
+				}
+				g.edges = append(g.edges, edge)
+				edge.to = add(followup, false)
+			}
+		}
+		return node
+	}
+
+	for _, card := range deck.Cards {
+		add(card, true).top = true
+	}
+	for _, card := range deck.FollowupCards {
+		add(card, true)
+	}
+	return g
+}
+
+// subtree keeps the cards with the ID and everything that can follow them
+func (g *storyGraph) subtree(id string) (*storyGraph, error) {
+	keep := make(map[*storyNode]bool)
+	var visit func(node *storyNode)
+	visit = func(node *storyNode) {
+		if keep[node] {
+			return
+		}
+		keep[node] = true
+		for _, edge := range g.edges {
+			if edge.from == node {
+				visit(edge.to)
+			}
+		}
+	}
+	for _, node := range g.nodes {
+		if node.card.ID == id {
+			visit(node)
+		}
+	}
+	if len(keep) == 0 {
+		return nil, fmt.Errorf("no card with ID %s", id)
+	}
+	return g.keep(keep), nil
+}
+
+// gatedBy keeps the cards whose requirements check the resource, and the
+// cards leading to them
+func (g *storyGraph) gatedBy(resource string) (*storyGraph, error) {
+	keep := make(map[*storyNode]bool)
+	var visit func(node *storyNode)
+	visit = func(node *storyNode) {
+		if keep[node] {
+			return
+		}
+		keep[node] = true
+		for _, edge := range g.edges {
+			if edge.to == node {
+				visit(edge.from)
+			}
+		}
+	}
+	for _, node := range g.nodes {
+		if requirementChecks(node.card.Requirements, resource) {
+			visit(node)
+		}
+	}
+	if len(keep) == 0 {
+		return nil, fmt.Errorf("no card requires %s", resource)
+	}
+	return g.keep(keep), nil
+}
+
+func (g *storyGraph) keep(keep map[*storyNode]bool) *storyGraph {
+	kept := &storyGraph{}
+	for _, node := range g.nodes {
+		if keep[node] {
+			kept.nodes = append(kept.nodes, node)
+		}
+	}
+	for _, edge := range g.edges {
+		if keep[edge.from] && keep[edge.to] {
+			kept.edges = append(kept.edges, edge)
+		}
+	}
+	return kept
+}
+
+// requirementChecks tells whether a requirement looks at a resource
+func requirementChecks(req *Requirement, resource string) bool {
+	if req == nil {
+		return false
+	}
+	for i := range req.Conditions {
+		if requirementChecks(&req.Conditions[i], resource) {
+			return true
+		}
+	}
+	return req.Type == "" && req.Resource == resource
+}
+
+// formatRequirement writes a requirement as an expression like
+// "boss >= 40 and (day > 10 or motivation < 20)"
+func formatRequirement(req *Requirement) string {
+	if req == nil {
+		return ""
+	}
+	if req.Type != "" {
+		parts := make([]string, len(req.Conditions))
+		for i := range req.Conditions {
+			parts[i] = formatRequirement(&req.Conditions[i])
+			if req.Conditions[i].Type != "" {
+				parts[i] = "(" + parts[i] + ")"
+			}
+		}
+		return strings.Join(parts, " "+req.Type+" ")
+	}
+	return fmt.Sprintf("%s %s %d", req.Resource, comparisonSymbols[req.Comparison], req.Value)
+}
+
+// label is the text of a node: the card ID and its requirement
+func (n *storyNode) label() []string {
+	lines := []string{n.card.ID}
+	if n.card.Requirements != nil {
+		lines = append(lines, "if "+formatRequirement(n.card.Requirements))
+	}
+	return lines
+}
+
+// label is the answer leading to a followup, its delay and its odds
+func (e *storyEdge) label() string {
+	var parts []string
+	if e.answer != "" {
+		parts = append(parts, e.answer)
+	}
+	switch {
+	case e.delay == 1:
+		parts = append(parts, "+1 day")
+	case e.delay > 1:
+		parts = append(parts, fmt.Sprintf("+%d days", e.delay))
+	}
+	if e.choices > 1 {
+		parts = append(parts, fmt.Sprintf("%.0f%%", e.chance*100))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Colors of the edges by answer
+var graphAnswerColors = map[string]string{
+	choiceYes: "#2e7d32",
+	choiceNo:  "#c62828",
+	"":        "#616161",
+}
+
+func (g *storyGraph) dot() []byte {
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph story {\n")
+	buf.WriteString("    rankdir=LR;\n")
+	buf.WriteString("    node [shape=box, style=rounded, fontname=\"Helvetica\"];\n")
+	buf.WriteString("    edge [fontname=\"Helvetica\", fontsize=10];\n\n")
+	for _, node := range g.nodes {
+		var attrs []string
+		attrs = append(attrs, "label="+quote(strings.Join(node.label(), "\n")))
+		style := "rounded"
+		if node.card.Requirements != nil {
+			style += ",dashed"
+		}
+		if node.top {
+			style += ",bold"
+		}
+		attrs = append(attrs, "style="+quote(style))
+		if node.card.IsInfoOnly {
+			attrs = append(attrs, "shape=note")
+		}
+		fmt.Fprintf(&buf, "    %s [%s];\n", node.id, strings.Join(attrs, ", "))
+	}
+	buf.WriteString("\n")
+	for _, edge := range g.edges {
+		fmt.Fprintf(&buf, "    %s -> %s [label=%s, color=%s, fontcolor=%s];\n",
+			edge.from.id, edge.to.id, quote(edge.label()), quote(graphAnswerColors[edge.answer]), quote(graphAnswerColors[edge.answer]))
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+func (g *storyGraph) mermaid() []byte {
+	// Mermaid reads entity codes written as #name;
+	escape := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+
+	var buf bytes.Buffer
+	buf.WriteString("flowchart LR\n")
+	var top []string
+	for _, node := range g.nodes {
+		lines := node.label()
+		for i, line := range lines {
+			lines[i] = escape.Replace(line)
+		}
+		label := `"` + strings.Join(lines, "<br/>") + `"`
+		// Requirements use the hexagon shape, the gate of the card
+		if node.card.Requirements != nil {
+			label = "{{" + label + "}}"
+		} else {
+			label = "[" + label + "]"
+		}
+		fmt.Fprintf(&buf, "    %s%s\n", node.id, label)
+		if node.top {
+			top = append(top, node.id)
+		}
+	}
+	for i, edge := range g.edges {
+		arrow := "-->"
+		if label := edge.label(); label != "" {
+			arrow = `-->|"` + escape.Replace(label) + `"|`
+		}
+		fmt.Fprintf(&buf, "    %s %s %s\n", edge.from.id, arrow, edge.to.id)
+		fmt.Fprintf(&buf, "    linkStyle %d stroke:%s\n", i, graphAnswerColors[edge.answer])
+	}
+	if len(top) > 0 {
+		buf.WriteString("    classDef top stroke-width:3px\n")
+		fmt.Fprintf(&buf, "    class %s top\n", strings.Join(top, ","))
+	}
+	return buf.Bytes()
+}
+
+// SVG layout, in pixels. Text is measured as monospace.
+const (
+	svgCharWidth  = 7.2
+	svgLineHeight = 16
+	svgPadding    = 8
+	svgRowGap     = 14
+	svgColumnGap  = 150
+	svgMargin     = 20
+)
+
+// svg lays the graph out as an outline: columns by followup depth and a
+// row per card in the order its first parent reaches it, so every card
+// sits right of and below the card leading to it
+func (g *storyGraph) svg() []byte {
+	type box struct {
+		x, y, width, height float64
+		column              int
+	}
+	children := make(map[*storyNode][]*storyNode)
+	hasParent := make(map[*storyNode]bool)
+	for _, edge := range g.edges {
+		children[edge.from] = append(children[edge.from], edge.to)
+		hasParent[edge.to] = true
+	}
+
+	boxes := make(map[*storyNode]*box)
+	var order []*storyNode
+	var place func(node *storyNode, column int)
+	place = func(node *storyNode, column int) {
+		if boxes[node] != nil {
+			return
+		}
+		width := 0.0
+		for _, line := range node.label() {
+			width = max(width, float64(len([]rune(line)))*svgCharWidth)
+		}
+		lines := float64(len(node.label()))
+		boxes[node] = &box{
+			width:  width + 2*svgPadding,
+			height: lines*svgLineHeight + 2*svgPadding,
+			column: column,
+		}
+		order = append(order, node)
+		for _, child := range children[node] {
+			place(child, column+1)
+		}
+	}
+	for _, node := range g.nodes {
+		if !hasParent[node] {
+			place(node, 0)
+		}
+	}
+	// Whatever is left is only reachable from itself
+	for _, node := range g.nodes {
+		place(node, 0)
+	}
+
+	var columnWidths []float64
+	for _, node := range order {
+		b := boxes[node]
+		for len(columnWidths) <= b.column {
+			columnWidths = append(columnWidths, 0)
+		}
+		columnWidths[b.column] = max(columnWidths[b.column], b.width)
+	}
+	columnX := make([]float64, len(columnWidths))
+	x := float64(svgMargin)
+	for i, width := range columnWidths {
+		columnX[i] = x
+		x += width + svgColumnGap
+	}
+	width := x - svgColumnGap + svgMargin
+
+	y := float64(svgMargin)
+	for _, node := range order {
+		b := boxes[node]
+		b.x = columnX[b.column]
+		b.y = y
+		y += b.height + svgRowGap
+	}
+	height := y - svgRowGap + svgMargin
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="monospace" font-size="12">`+"\n", width, height, width, height)
+	buf.WriteString("<defs>\n")
+	for _, answer := range []string{choiceYes, choiceNo, ""} {
+		fmt.Fprintf(&buf, `<marker id="arrow%s" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto"><path d="M0,0 L10,5 L0,10 z" fill="%s"/></marker>`+"\n", answer, graphAnswerColors[answer])
+	}
+	buf.WriteString("</defs>\n")
+
+	incoming := make(map[*storyNode]int)
+	for _, edge := range g.edges {
+		from, to := boxes[edge.from], boxes[edge.to]
+		x1, y1 := from.x+from.width, from.y+from.height/2
+		x2, y2 := to.x, to.y+to.height/2
+		bend := max(40, (x2-x1)/2)
+		color := graphAnswerColors[edge.answer]
+		fmt.Fprintf(&buf, `<path d="M%.1f,%.1f C%.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="none" stroke="%s" stroke-width="1.5" marker-end="url(#arrow%s)"/>`+"\n",
+			x1, y1, x1+bend, y1, x2-bend, y2, x2, y2, color, edge.answer)
+		// Labels sit by the followup, which has a row of its own, stacked
+		// when several cards lead to it
+		if label := edge.label(); label != "" {
+			fmt.Fprintf(&buf, `<text x="%.1f" y="%.1f" fill="%s" font-size="10" text-anchor="end" stroke="white" stroke-width="3" paint-order="stroke">%s</text>`+"\n",
+				x2-12, y2-5-float64(incoming[edge.to])*11, color, html.EscapeString(label))
+			incoming[edge.to]++
+		}
+	}
+
+	for _, node := range order {
+		b := boxes[node]
+		fill := "#ffffff"
+		switch {
+		case node.top:
+			fill = "#e3f2fd"
+		case node.card.IsInfoOnly:
+			fill = "#f5f5f5"
+		}
+		dash := ""
+		if node.card.Requirements != nil {
+			dash = ` stroke-dasharray="5,3"`
+		}
+		fmt.Fprintf(&buf, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="6" fill="%s" stroke="#37474f"%s/>`+"\n", b.x, b.y, b.width, b.height, fill, dash)
+		for i, line := range node.label() {
+			weight := ""
+			if i == 0 {
+				weight = ` font-weight="bold"`
+			}
+			fmt.Fprintf(&buf, `<text x="%.1f" y="%.1f"%s>%s</text>`+"\n", b.x+svgPadding, b.y+svgPadding+float64(i+1)*svgLineHeight-4, weight, html.EscapeString(line))
+		}
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+func runGraph(args []string, assetDir string) error {
+	flags := flag.NewFlagSet("graph", flag.ContinueOnError)
+	format := flags.String("format", "", "Output format: dot, mermaid or svg. Defaults to the output file extension, or dot.")
+	output := flags.String("o", "", "Write the graph to this file instead of standard output")
+	card := flags.String("card", "", "Only show the card with this ID and what can follow it")
+	resource := flags.String("resource", "", "Only show cards whose requirements check this resource, and what leads to them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *format == "" {
+		*format = "dot"
+		if byExt, ok := graphExtensions[strings.ToLower(filepath.Ext(*output))]; ok {
+			*format = byExt
+		}
+	}
+	if *resource != "" && !slices.Contains(requirementResources, *resource) {
+		return fmt.Errorf("unknown resource %q, use one of %s", *resource, strings.Join(requirementResources, ", "))
+	}
+	write, ok := graphFormats[*format]
+	if !ok {
+		return fmt.Errorf("unknown format %q, use dot, mermaid or svg", *format)
+	}
+
+	deck, err := readDeckArg(flags.Args(), assetDir)
+	if err != nil {
+		return err
+	}
+	graph := buildStoryGraph(deck)
+	if *card != "" {
+		if graph, err = graph.subtree(*card); err != nil {
+			return err
+		}
+	}
+	if *resource != "" {
+		if graph, err = graph.gatedBy(*resource); err != nil {
+			return err
+		}
+	}
+
+	data := write(graph)
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0o644)
+}
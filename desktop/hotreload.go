@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"slices"
+	"strings"
+)
+
+// Ticks between two checks of the deck file for changes
+const deckPollTicks = 60
+
+// Names a requirement may use, matching checkRequirements
+var (
+	requirementTypes       = []string{"and", "or"}
+	requirementResources   = []string{"motivation", "performance", "colleagues", "boss", "day"}
+	requirementComparisons = []string{"gt", "lt", "gte", "lte", "eq"}
+)
+
+// readDeck reads, assembles and validates a deck and the files it
+// includes from fsys. It also returns what to watch for changes.
+func readDeck(fsys fs.FS, filename string) (*Deck, []byte, []string, error) {
+	deck, data, watch, err := assembleDeck(fsys, filename)
+	if err != nil {
+		return nil, data, watch, err
+	}
+	if err := validateDeck(deck); err != nil {
+		return nil, data, watch, err
+	}
+	return deck, data, watch, nil
+}
+
+// positionedError adds the line and column to JSON decoding errors
+func positionedError(data []byte, err error) error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+
+	line, column := lineColumn(data, offset)
+	return fmt.Errorf("line %d, column %d: %w", line, column, err)
+}
+
+// lineColumn turns a byte offset into a line and a column counted in
+// characters, both starting at 1
+func lineColumn(data []byte, offset int64) (line, column int) {
+	offset = max(0, min(offset, int64(len(data))))
+
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len([]rune(string(before[bytes.LastIndexByte(before, '\n')+1:]))) + 1
+	return line, column
+}
+
+// validateDeck checks what the JSON decoder can't: IDs, speakers and
+// requirement names. Errors name the file and line of the card when the
+// deck was read from files.
+func validateDeck(deck *Deck) error {
+	if len(deck.Cards) == 0 {
+		return errors.New("deck has no cards")
+	}
+
+	characters := make(map[string]bool, len(deck.Characters))
+	for _, character := range deck.Characters {
+		characters[character.ID] = true
+	}
+
+	var errs []error
+	var check func(cards []*Card)
+	check = func(cards []*Card) {
+		for _, card := range cards {
+			// Copies made for refs are checked where the card is defined
+			if !card.fromRef {
+				errs = append(errs, validateCard(deck, card, characters)...)
+				check(card.YesFollowups)
+				check(card.NoFollowups)
+				check(card.Followups)
+			}
+		}
+	}
+	check(deck.definedCards())
+	return errors.Join(errs...)
+}
+
+func validateCard(deck *Deck, card *Card, characters map[string]bool) (errs []error) {
+	name := card.ID
+	if name == "" {
+		name = fmt.Sprintf("%q", firstWords(card.Text, 4))
+		errs = append(errs, deck.errorf(card, "card %s has no id", name))
+	}
+	if card.Speaker != "" && len(deck.Characters) > 0 && !characters[card.Speaker] {
+		errs = append(errs, deck.errorf(card, "card %s: unknown speaker %q", name, card.Speaker))
+	}
+	if err := validateRequirement(card.Requirements); err != nil {
+		errs = append(errs, deck.errorf(card, "card %s: %w", name, err))
+	}
+	return errs
+}
+
+func validateRequirement(req *Requirement) error {
+	if req == nil {
+		return nil
+	}
+
+	if req.Type != "" {
+		if !slices.Contains(requirementTypes, req.Type) {
+			return fmt.Errorf("unknown requirement type %q", req.Type)
+		}
+		if len(req.Conditions) == 0 {
+			return fmt.Errorf("%q requirement has no conditions", req.Type)
+		}
+		for i := range req.Conditions {
+			if err := validateRequirement(&req.Conditions[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !slices.Contains(requirementResources, req.Resource) {
+		return fmt.Errorf("unknown requirement resource %q", req.Resource)
+	}
+	if !slices.Contains(requirementComparisons, req.Comparison) {
+		return fmt.Errorf("unknown requirement comparison %q", req.Comparison)
+	}
+	return nil
+}
+
+// firstWords shortens text to name a card that has no ID
+func firstWords(text string, n int) string {
+	words := strings.Fields(text)
+	if len(words) > n {
+		return strings.Join(words[:n], " ") + "..."
+	}
+	return strings.Join(words, " ")
+}
+
+// updateDeckWatch polls the deck and pack files and reloads the deck when
+// one of them changes.
+// Replays need the deck they were recorded with, so it is left alone
+// during playback.
+func (g *Game) updateDeckWatch() {
+	if g.deckFile == "" || g.player != nil {
+		return
+	}
+
+	g.deckPollTick++
+	if g.deckPollTick < deckPollTicks {
+		return
+	}
+	g.deckPollTick = 0
+
+	if deckModTime(g.deckWatch).Equal(g.deckModTime) {
+		return
+	}
+	g.reloadDeck()
+}
+
+// reloadDeck re-reads the deck file and swaps it into the running game.
+// A broken file keeps the current deck and is reported on screen. A deck
+// that hashes the same is left alone, so saving without changes keeps the
+// run on the records.
+func (g *Game) reloadDeck() error {
+	deck, hash, err := g.buildDeck(g.deckFile)
+	g.deckModTime = deckModTime(g.deckWatch)
+	if err != nil {
+		log.Printf("Failed to reload deck: %v", err)
+		g.deckError = err
+		return err
+	}
+
+	g.deckError = nil
+	if hash == g.deckHash {
+		log.Printf("Deck %s unchanged", g.deckFile)
+		return nil
+	}
+	g.swapDeck(deck, hash)
+	log.Printf("Reloaded deck %s", g.deckFile)
+	return nil
+}
+
+// swapDeck replaces the cards of the running game, keeping use counts,
+// the shuffle pool and pending followups for cards whose IDs still exist
+func (g *Game) swapDeck(deck *Deck, hash string) {
+	uses := make(map[string]int)
+	forEachCard(g.cards, func(card *Card) {
+		uses[card.ID] = max(uses[card.ID], card.Uses)
+	})
+	available := make(map[string]bool, len(g.availableCards))
+	for _, card := range g.availableCards {
+		available[card.ID] = true
+	}
+	known := make(map[string]bool, len(g.cards))
+	for _, card := range g.cards {
+		known[card.ID] = true
+	}
+
+	byID := make(map[string]*Card)
+	forEachCard(deck.Cards, func(card *Card) {
+		card.Uses = uses[card.ID]
+		if byID[card.ID] == nil {
+			byID[card.ID] = card
+		}
+	})
+
+	// Cards new to the deck join the current shuffle
+	g.cards = deck.Cards
+	g.availableCards = nil
+	for _, card := range deck.Cards {
+		if available[card.ID] || !known[card.ID] {
+			g.availableCards = append(g.availableCards, card)
+		}
+	}
+
+	var delayed []FollowupCardItem
+	for _, item := range g.delayedCards {
+		card := byID[item.Card.ID]
+		if card == nil {
+			continue
+		}
+		card.ParentCardID = item.ParentCardID
+		item.Card = card
+		delayed = append(delayed, item)
+	}
+	g.delayedCards = delayed
+
+	if g.currentCard != nil {
+		if card := byID[g.currentCard.ID]; card != nil {
+			g.currentCard = card
+		}
+	}
+
+	g.deckHash = hash
+	g.applyDeck(deck)
+
+	// Snapshots point at the old cards, and the recording no longer
+	// matches any one deck. The HUD shows the run is not recorded.
+	g.undoStack = nil
+	g.debugRun = true
+}
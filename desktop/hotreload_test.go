@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestConsoleReload(t *testing.T) {
+	const deck = `{"schemaVersion": 2, "cards": [{"id": "A", "text": "a"}, {"id": "B", "text": "b"}]}`
+	const changed = `{"schemaVersion": 2, "cards": [{"id": "A", "text": "new a"}, {"id": "B", "text": "b"}]}`
+
+	tests := []struct {
+		name         string
+		commands     []string
+		reloaded     string // Deck file contents before the commands, empty to keep it
+		wantDebugRun bool
+		wantText     string // Text of card A afterwards
+		wantOutput   string // Last console line
+	}{
+		{"unchanged deck stays recorded", []string{"reload"}, "", false, "a", "deck unchanged"},
+		{"same deck saved again", []string{"reload"}, deck, false, "a", "deck unchanged"},
+		{"changed deck", []string{"reload"}, changed, true, "new a", "deck reloaded, this run is no longer recorded"},
+		{"reload after a cheat", []string{"set motivation 90", "reload"}, "", true, "a", "deck unchanged"},
+		{"broken deck", []string{"reload"}, `{"cards": [`, false, "a", "usage: reload"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"deck.json": {Data: []byte(deck)}}
+			saved := assets
+			assets = fsys
+			t.Cleanup(func() { assets = saved })
+
+			g := &Game{}
+			if err := g.loadCards("deck.json"); err != nil {
+				t.Fatal(err)
+			}
+			if tt.reloaded != "" {
+				fsys["deck.json"] = &fstest.MapFile{Data: []byte(tt.reloaded)}
+			}
+			for _, command := range tt.commands {
+				g.runConsoleCommand(command)
+			}
+
+			if g.debugRun != tt.wantDebugRun {
+				t.Errorf("debugRun = %v, want %v", g.debugRun, tt.wantDebugRun)
+			}
+			if a := g.findCard("A"); a.Text != tt.wantText {
+				t.Errorf("card A text %q, want %q", a.Text, tt.wantText)
+			}
+			if got := g.console.output[len(g.console.output)-1]; got != tt.wantOutput {
+				t.Errorf("console output %q, want %q", got, tt.wantOutput)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+// Language is a UI language the game can be switched to. Card text comes
+// from the deck and is not translated.
+type Language struct {
+	Code string
+	Name string
+}
+
+var languages = []Language{
+	{Code: "tr", Name: "Türkçe"},
+	{Code: "en", Name: "English"},
+}
+
+// Active UI language code
+var currentLanguage = "tr"
+
+// UI strings per language, keyed by message ID
+var translations = map[string]map[string]string{
+	"tr": {
+		"day":              "Gün %d",
+		"restart":          "Yeniden Başla",
+		"welcome":          "Hazırsanız başlayalım",
+		"yes":              "Evet",
+		"no":               "Hayır",
+		"swipeHint":        "Kaydırmak için sürükle",
+		"gameOver":         "Oyun Bitti!",
+		"daysSurvived":     "%d gün dayanabildiniz.",
+		"copyright":        "© 2025 Office Politics.",
+		"about.title":      "Office Politics Hakkında",
+		"about.body":       "Bu oyun, Reigns oyunundan ilham alınarak yapılmıştır.\n\nOffice Politics, ofis hayatındaki kararları simüle eden bir oyundur.\nKartları sağa veya sola kaydırarak kararlar verin ve\n(M) motivasyon, (P) performans, (A) iş arkadaşları ve (P) patron memnuniyetini\ndengelemeye çalışarak oyunu kazanmaya çalışın.\n\nKapatmak için herhangi bir yere tıklayın.",
+		"stat.motivation":  "Motivasyon",
+		"stat.performance": "Performans",
+		"stat.colleagues":  "İş Arkadaşları",
+		"stat.boss":        "Patron",
+
+		"ending.motivationLow":   "Motivasyonunuz tükendi. İşi bıraktınız.",
+		"ending.motivationHigh":  "Aşırı motivasyon sizi tüketti. Burnout oldunuz.",
+		"ending.performanceLow":  "Performansınız çok düşük. Kovuldunuz.",
+		"ending.performanceHigh": "Çok fazla çalıştınız. Tükenmişlik sendromu yaşadınız.",
+		"ending.colleaguesLow":   "İş arkadaşlarınız sizden nefret ediyor. Yalnız kaldınız ve istifa ettiniz.",
+		"ending.colleaguesHigh":  "İş arkadaşlarınızla çok yakınsınız. Bu aranızdaki sosyalliğin artmasına ve iş yerine sosyal kulüp muamelesi yapmanıza sebep oldu. Kovuldunuz.",
+		"ending.bossLow":         "Patronunuz sizi sevmiyor. Kovuldunuz.",
+		"ending.bossHigh":        "Patronunuz sizi çok seviyor. Terfi ettiniz ve oyunu kazandınız!",
+		"ending.competitorOffer": "Rakip firmadan gelen teklifi kabul ettiniz ve yeni bir başlangıç yaptınız. Oyunu kazandınız!",
+
+		"settings.title":            "Ayarlar",
+		"settings.language":         "Dil",
+		"settings.theme":            "Tema",
+		"settings.sfxVolume":        "Efekt sesi",
+		"settings.musicVolume":      "Müzik",
+		"settings.muted":            "Sesi kapat",
+		"settings.animationSpeed":   "Animasyon hızı",
+		"settings.reducedMotion":    "Azaltılmış hareket",
+		"settings.textSize":         "Yazı boyutu",
+		"settings.swipeSensitivity": "Kaydırma hassasiyeti",
+		"settings.close":            "Kapat",
+		"menu.title":                "Menü",
+		"stats.title":               "İstatistikler",
+		"stats.empty":               "Henüz tamamlanmış bir oyun yok.",
+		"stats.totalRuns":           "Oynanan oyun: %d",
+		"stats.totalDays":           "Toplam gün: %d",
+		"stats.averageDays":         "Ortalama gün: %.1f",
+		"stats.bestDays":            "En uzun oyun: %d gün",
+		"stats.wins":                "Kazanılan oyun: %d",
+		"stats.winStreak":           "En uzun galibiyet serisi: %d (şu anki: %d)",
+		"stats.commonEnding":        "En sık oyun sonu:",
+		"personalBest":              "Kişisel rekor: %d gün",
+		"newPersonalBest":           "Yeni kişisel rekor!",
+		"gallery.title":             "Oyun Sonları",
+		"gallery.progress":          "Keşfedilen: %d / %d",
+		"gallery.details":           "Gün %d · Oyun #%d · %d kez",
+		"gallery.newEnding":         "Yeni oyun sonu keşfedildi! (%d / %d)",
+		"achievements.title":        "Başarımlar",
+		"achievements.progress":     "Açılan: %d / %d",
+		"achievements.empty":        "Bu destede başarım tanımlanmamış.",
+		"achievements.locked":       "Kilitli",
+		"achievements.unlocked":     "Başarım açıldı!",
+		"journal.title":             "Karar Günlüğü",
+		"journal.empty":             "Bu oyunda henüz bir karar vermediniz.",
+		"journal.info":              "Bilgi",
+		"journal.noChange":          "Değişiklik yok",
+		"journal.causedBy":          "← Gün %d kararının sonucu (%s)",
+		"replay.playing":            "Oynatılıyor",
+		"replay.paused":             "Duraklatıldı",
+		"replay.status":             "Tekrar: %s · Adım %d / %d · Hız %s",
+		"replay.controls":           "Boşluk: duraklat · →: adım · +/-: hız",
+		"replay.failed":             "Tekrar oynatılamadı!",
+		"daily.title":               "Günlük Görev",
+		"daily.start":               "Başla",
+		"daily.available":           "Bugünkü görev sizi bekliyor. Tek bir deneme hakkınız var.",
+		"daily.abandoned":           "Yarım kaldı",
+		"daily.won":                 "Kazanıldı, %d gün",
+		"daily.lost":                "%d gün",
+		"daily.streak":              "Seri: %d gün (en iyi: %d)",
+		"daily.history":             "Son günler:",
+		"profiles.title":            "Profiller",
+		"profiles.new":              "Yeni profil",
+		"profiles.nameHint":         "İsim yazıp Enter'a basın, vazgeçmek için Esc",
+		"profiles.defaultName":      "Oyuncu",
+		"leaderboard.title":         "Skor Tablosu",
+		"leaderboard.standard":      "Standart",
+		"leaderboard.difficulty":    "Zorluk: %s",
+		"leaderboard.empty":         "Bu tabloda henüz bir oyun yok.",
+		"leaderboard.result":        "%d gün · %d puan",
+		"difficulty.normal":         "Normal",
+		"casual.title":              "Rahat Mod",
+		"settings.casual":           "Rahat mod (yeni oyunlarda)",
+		"settings.undoLimit":        "Geri alma hakkı",
+		"stats.undoRuns":            "Geri alma kullanılan oyun: %d",
+		"leaderboard.undoNote":      "* Geri alma kullanıldı",
+		"deck.error":                "Deste yüklenemedi:",
+		"run.unrecorded":            "Kaydedilmiyor",
+		"assets.error":              "Bazı dosyalar yüklenemedi:",
+		"assets.dismiss":            "Kapatmak için tıklayın",
+		"packs.title":               "Deste Paketleri",
+		"packs.empty":               "Paket bulunamadı. Paketler şu klasöre konur: %s",
+		"packs.broken":              "Bozuk",
+		"packs.details":             "Yazar: %s",
+		"packs.dependencies":        "Gerekenler: %s",
+		"packs.report":              "Yükleme raporu:",
+		"packs.noConflicts":         "Çakışma yok.",
+		"on":                        "Açık",
+		"off":                       "Kapalı",
+	},
+	"en": {
+		"day":              "Day %d",
+		"restart":          "Restart",
+		"welcome":          "Ready when you are",
+		"yes":              "Yes",
+		"no":               "No",
+		"swipeHint":        "Drag to swipe",
+		"gameOver":         "Game Over!",
+		"daysSurvived":     "You lasted %d days.",
+		"copyright":        "© 2025 Office Politics.",
+		"about.title":      "About Office Politics",
+		"about.body":       "This game is inspired by Reigns.\n\nOffice Politics simulates the decisions of office life.\nSwipe cards left or right to decide and try to win by\nbalancing (M) motivation, (P) performance, (A) colleagues and (P) your boss's opinion.\n\nClick anywhere to close.",
+		"stat.motivation":  "Motivation",
+		"stat.performance": "Performance",
+		"stat.colleagues":  "Colleagues",
+		"stat.boss":        "Boss",
+
+		"ending.motivationLow":   "You ran out of motivation and quit.",
+		"ending.motivationHigh":  "Too much motivation wore you out. You burned out.",
+		"ending.performanceLow":  "Your performance was too low. You were fired.",
+		"ending.performanceHigh": "You worked far too hard and burned out.",
+		"ending.colleaguesLow":   "Your colleagues hate you. Left alone, you resigned.",
+		"ending.colleaguesHigh":  "You got so close to your colleagues that the office turned into a social club. You were fired.",
+		"ending.bossLow":         "Your boss doesn't like you. You were fired.",
+		"ending.bossHigh":        "Your boss loves you. You were promoted and won the game!",
+		"ending.competitorOffer": "You accepted the competitor's offer and made a fresh start. You won the game!",
+
+		"settings.title":            "Settings",
+		"settings.language":         "Language",
+		"settings.theme":            "Theme",
+		"settings.sfxVolume":        "Effects volume",
+		"settings.musicVolume":      "Music",
+		"settings.muted":            "Mute",
+		"settings.animationSpeed":   "Animation speed",
+		"settings.reducedMotion":    "Reduced motion",
+		"settings.textSize":         "Text size",
+		"settings.swipeSensitivity": "Swipe sensitivity",
+		"settings.close":            "Close",
+		"menu.title":                "Menu",
+		"stats.title":               "Statistics",
+		"stats.empty":               "No finished games yet.",
+		"stats.totalRuns":           "Games played: %d",
+		"stats.totalDays":           "Total days: %d",
+		"stats.averageDays":         "Average days: %.1f",
+		"stats.bestDays":            "Longest game: %d days",
+		"stats.wins":                "Games won: %d",
+		"stats.winStreak":           "Best win streak: %d (current: %d)",
+		"stats.commonEnding":        "Most common ending:",
+		"personalBest":              "Personal best: %d days",
+		"newPersonalBest":           "New personal best!",
+		"gallery.title":             "Endings",
+		"gallery.progress":          "Discovered: %d / %d",
+		"gallery.details":           "Day %d · Game #%d · %d times",
+		"gallery.newEnding":         "New ending discovered! (%d / %d)",
+		"achievements.title":        "Achievements",
+		"achievements.progress":     "Unlocked: %d / %d",
+		"achievements.empty":        "This deck has no achievements.",
+		"achievements.locked":       "Locked",
+		"achievements.unlocked":     "Achievement unlocked!",
+		"journal.title":             "Decision Journal",
+		"journal.empty":             "You haven't made any decisions in this game yet.",
+		"journal.info":              "Info",
+		"journal.noChange":          "No change",
+		"journal.causedBy":          "← Result of the day %d decision (%s)",
+		"replay.playing":            "Playing",
+		"replay.paused":             "Paused",
+		"replay.status":             "Replay: %s · Step %d / %d · Speed %s",
+		"replay.controls":           "Space: pause · →: step · +/-: speed",
+		"replay.failed":             "Replay failed!",
+		"daily.title":               "Daily Challenge",
+		"daily.start":               "Start",
+		"daily.available":           "Today's challenge is waiting. You get one attempt.",
+		"daily.abandoned":           "Abandoned",
+		"daily.won":                 "Won, %d days",
+		"daily.lost":                "%d days",
+		"daily.streak":              "Streak: %d days (best: %d)",
+		"daily.history":             "Recent days:",
+		"profiles.title":            "Profiles",
+		"profiles.new":              "New profile",
+		"profiles.nameHint":         "Type a name and press Enter, Esc to cancel",
+		"profiles.defaultName":      "Player",
+		"leaderboard.title":         "Leaderboard",
+		"leaderboard.standard":      "Standard",
+		"leaderboard.difficulty":    "Difficulty: %s",
+		"leaderboard.empty":         "No games on this board yet.",
+		"leaderboard.result":        "%d days · %d points",
+		"difficulty.normal":         "Normal",
+		"casual.title":              "Casual",
+		"settings.casual":           "Casual mode (new games)",
+		"settings.undoLimit":        "Undos per game",
+		"stats.undoRuns":            "Games with undo: %d",
+		"leaderboard.undoNote":      "* Undo was used",
+		"deck.error":                "Failed to load the deck:",
+		"run.unrecorded":            "Not recorded",
+		"assets.error":              "Some files failed to load:",
+		"assets.dismiss":            "Click to dismiss",
+		"packs.title":               "Deck Packs",
+		"packs.empty":               "No packs found. Packs go in: %s",
+		"packs.broken":              "Broken",
+		"packs.details":             "By %s",
+		"packs.dependencies":        "Needs: %s",
+		"packs.report":              "Load report:",
+		"packs.noConflicts":         "No conflicts.",
+		"on":                        "On",
+		"off":                       "Off",
+	},
+}
+
+// tr returns the message in the active language, falling back to Turkish
+// and then to the key itself
+func tr(key string) string {
+	if msg, ok := translations[currentLanguage][key]; ok {
+		return msg
+	}
+	if msg, ok := translations["tr"][key]; ok {
+		return msg
+	}
+	return key
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"path"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// imageCache loads card illustrations and portraits from the deck's asset
+// directory and keeps them for reuse. Missing images are remembered too so
+// a bad path is only reported once.
+type imageCache struct {
+	dir    string
+	images map[string]*ebiten.Image
+}
+
+func newImageCache(dir string) *imageCache {
+	return &imageCache{
+		dir:    dir,
+		images: make(map[string]*ebiten.Image),
+	}
+}
+
+// get returns the image at the given path relative to the asset directory,
+// or nil if it cannot be loaded
+func (c *imageCache) get(name string) *ebiten.Image {
+	if c == nil || name == "" {
+		return nil
+	}
+
+	if img, ok := c.images[name]; ok {
+		return img
+	}
+
+	img, err := loadImage(path.Join(c.dir, name))
+	if err != nil {
+		reportAssetError(fmt.Errorf("image %s: %w", name, err))
+	}
+	c.images[name] = img
+	return img
+}
+
+func loadImage(name string) (*ebiten.Image, error) {
+	f, err := assets.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	decoded, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return ebiten.NewImageFromImage(decoded), nil
+}
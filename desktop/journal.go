@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font"
+)
+
+// The welcome card starts every run and is not a decision
+const welcomeCardID = "WELCOME"
+
+// Answers recorded in the journal
+const (
+	choiceYes  = "yes"
+	choiceNo   = "no"
+	choiceInfo = "info"
+)
+
+// Journal rows, in unscaled units
+const (
+	journalRowHeight = 84.0
+	journalRowGap    = 8.0
+	journalIndent    = 24.0
+	journalScrollKey = 40.0
+)
+
+// JournalEntry is one resolved card in the current run
+type JournalEntry struct {
+	Day         int
+	CardID      string
+	Text        string
+	Speaker     string
+	Choice      string  // choiceYes, choiceNo or choiceInfo
+	ChoiceLabel string  // The answer text shown on the card
+	Deltas      Effects // Actual stat changes after scaling and clamping
+	CausedBy    int     // Entry whose decision queued this card, -1 if none
+}
+
+// newJournalEntry describes the answer given to the current card
+func (g *Game) newJournalEntry(isYes bool) JournalEntry {
+	card := g.currentCard
+	entry := JournalEntry{
+		Day:      g.resources.Day,
+		CardID:   card.ID,
+		Text:     card.Text,
+		Speaker:  card.Speaker,
+		Choice:   choiceNo,
+		CausedBy: g.currentCause,
+	}
+
+	switch {
+	case card.IsInfoOnly:
+		entry.Choice = choiceInfo
+	case isYes:
+		entry.Choice = choiceYes
+		entry.ChoiceLabel = card.YesText
+	default:
+		entry.ChoiceLabel = card.NoText
+	}
+	return entry
+}
+
+// addJournalEntry appends the entry and returns its index, or -1 for the
+// welcome card which is not recorded
+func (g *Game) addJournalEntry(entry JournalEntry) int {
+	if entry.CardID == welcomeCardID {
+		return -1
+	}
+	g.journal = append(g.journal, entry)
+	return len(g.journal) - 1
+}
+
+// resourceDeltas returns how much each stat changed
+func resourceDeltas(before, after Resources) Effects {
+	return Effects{
+		Motivation:  after.Motivation - before.Motivation,
+		Performance: after.Performance - before.Performance,
+		Colleagues:  after.Colleagues - before.Colleagues,
+		Boss:        after.Boss - before.Boss,
+	}
+}
+
+// formatDeltas lists the stats that changed, e.g. "Motivasyon +5, Patron -3"
+func formatDeltas(d Effects) string {
+	var parts []string
+	for _, stat := range []struct {
+		key   string
+		value int
+	}{
+		{"stat.motivation", d.Motivation},
+		{"stat.performance", d.Performance},
+		{"stat.colleagues", d.Colleagues},
+		{"stat.boss", d.Boss},
+	} {
+		if stat.value != 0 {
+			parts = append(parts, fmt.Sprintf("%s %+d", tr(stat.key), stat.value))
+		}
+	}
+
+	if len(parts) == 0 {
+		return tr("journal.noChange")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// firstLine returns the text cut to one line of the given width
+func firstLine(content string, face font.Face, width int) string {
+	lines := wrapText(content, face, width)
+	if len(lines) == 0 {
+		return ""
+	}
+	if len(lines) > 1 {
+		return lines[0] + "..."
+	}
+	return lines[0]
+}
+
+// journalGeometry lays out the journal panel and the scrolling list inside it
+func (g *Game) journalGeometry() (panel, list Rect) {
+	l := g.layout
+	s := l.Scale
+
+	width := min(520*s, l.Width-20*s)
+	height := l.Height - 40*s
+	panel = Rect{X: (l.Width - width) / 2, Y: 20 * s, Width: width, Height: height}
+	list = Rect{X: panel.X + 20*s, Y: panel.Y + 70*s, Width: width - 40*s, Height: height - 90*s}
+	return panel, list
+}
+
+// journalRows returns the rectangle of every entry, before scrolling
+func (g *Game) journalRows(list Rect) []Rect {
+	s := g.layout.Scale
+	rows := make([]Rect, len(g.journal))
+	y := list.Y
+	for i, entry := range g.journal {
+		x := list.X
+		if entry.CausedBy >= 0 {
+			x += journalIndent * s
+		}
+		rows[i] = Rect{X: x, Y: y, Width: list.X + list.Width - x, Height: journalRowHeight * s}
+		y += (journalRowHeight + journalRowGap) * s
+	}
+	return rows
+}
+
+// maxJournalScroll is how far the list can scroll before its end is visible
+func (g *Game) maxJournalScroll(list Rect) float64 {
+	s := g.layout.Scale
+	content := float64(len(g.journal))*(journalRowHeight+journalRowGap)*s - journalRowGap*s
+	return max(0, content-list.Height)
+}
+
+// updateJournal handles scrolling and closing the journal
+func (g *Game) updateJournal() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.returnToGame()
+		return
+	}
+
+	panel, list := g.journalGeometry()
+	s := g.layout.Scale
+
+	// Mouse wheel and keyboard scrolling
+	_, wheel := ebiten.Wheel()
+	g.journalScroll -= wheel * journalScrollKey * s
+	if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+		g.journalScroll += journalScrollKey * s
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+		g.journalScroll -= journalScrollKey * s
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyPageDown) {
+		g.journalScroll += list.Height
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyPageUp) {
+		g.journalScroll -= list.Height
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnd) {
+		g.journalScroll = g.maxJournalScroll(list)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyHome) {
+		g.journalScroll = 0
+	}
+	g.journalScroll = max(0, min(g.journalScroll, g.maxJournalScroll(list)))
+
+	// Click outside the panel closes the journal
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		mx, my := ebiten.CursorPosition()
+		if !panel.Contains(float64(mx), float64(my)) {
+			g.returnToGame()
+		}
+	}
+}
+
+func (g *Game) drawJournalScreen(screen *ebiten.Image) {
+	l := g.layout
+	s := l.Scale
+	panel, list := g.journalGeometry()
+
+	// Draw overlay and panel
+	drawOverlay(screen, l)
+	vector.DrawFilledRect(screen, float32(panel.X), float32(panel.Y), float32(panel.Width), float32(panel.Height), colorPanel, true)
+
+	// Title
+	title := tr("journal.title")
+	w, _ := getBoundsSize(boldFont, title)
+	drawTextWithOptions(screen, title, boldFont, int(panel.CenterX())-w/2, int(panel.Y+40*s), colorTextLight)
+
+	if len(g.journal) == 0 {
+		drawWrappedText(screen, tr("journal.empty"), regularFont,
+			int(list.X), int(list.Y+20*s), int(list.Width), colorTextLight)
+		return
+	}
+
+	// Hovering an entry highlights the decision it came from
+	rows := g.journalRows(list)
+	mx, my := ebiten.CursorPosition()
+	hovered := -1
+	if list.Contains(float64(mx), float64(my)) {
+		for i, row := range rows {
+			if row.Contains(float64(mx), float64(my)+g.journalScroll) {
+				hovered = i
+			}
+		}
+	}
+	highlighted := -1
+	if hovered >= 0 {
+		highlighted = g.journal[hovered].CausedBy
+	}
+
+	// Entries are clipped to the list area
+	clip := screen.SubImage(image.Rect(int(list.X), int(list.Y), int(list.X+list.Width), int(list.Y+list.Height))).(*ebiten.Image)
+	padding := 10 * s
+	for i, entry := range g.journal {
+		row := rows[i]
+		row.Y -= g.journalScroll
+		if row.Y+row.Height < list.Y || row.Y > list.Y+list.Height {
+			continue
+		}
+
+		border := colorCardBorder
+		if i == highlighted {
+			border = colorYesOption
+		}
+		drawCardShape(clip, float32(row.X), float32(row.Y), float32(row.Width), float32(row.Height), s, colorCard, border)
+
+		// Link from a followup back to its decision
+		if entry.CausedBy >= 0 {
+			vector.StrokeLine(clip, float32(list.X+journalIndent*s/2), float32(row.Y-journalRowGap*s),
+				float32(list.X+journalIndent*s/2), float32(row.CenterY()), float32(2*s), colorCardBorder, true)
+			vector.StrokeLine(clip, float32(list.X+journalIndent*s/2), float32(row.CenterY()),
+				float32(row.X), float32(row.CenterY()), float32(2*s), colorCardBorder, true)
+		}
+
+		textX := int(row.X + padding)
+		textWidth := int(row.Width - 2*padding)
+		lineHeight := lineHeightFor(smallFont)
+		y := int(row.Y + padding + 14*s)
+
+		// Day and answer
+		header := fmt.Sprintf(tr("day"), entry.Day) + " · " + g.journalChoiceText(entry)
+		drawTextWithOptions(clip, firstLine(header, regularFont, textWidth), regularFont, textX, y, colorTextPrimary)
+		y += lineHeightFor(regularFont)
+
+		drawTextWithOptions(clip, firstLine(entry.Text, smallFont, textWidth), smallFont, textX, y, colorTextPrimary)
+		y += lineHeight
+
+		drawTextWithOptions(clip, firstLine(formatDeltas(entry.Deltas), smallFont, textWidth), smallFont, textX, y, colorTextPrimary)
+		y += lineHeight
+
+		if entry.CausedBy >= 0 {
+			cause := g.journal[entry.CausedBy]
+			link := fmt.Sprintf(tr("journal.causedBy"), cause.Day, g.journalChoiceText(cause))
+			drawTextWithOptions(clip, firstLine(link, smallFont, textWidth), smallFont, textX, y, withOpacity(colorTextPrimary, 0.7))
+		}
+	}
+}
+
+// journalChoiceText names the answer, e.g. "Evet: Kabul ediyorum"
+func (g *Game) journalChoiceText(entry JournalEntry) string {
+	switch entry.Choice {
+	case choiceInfo:
+		return tr("journal.info")
+	case choiceYes:
+		if entry.ChoiceLabel == "" {
+			return tr("yes")
+		}
+		return tr("yes") + ": " + entry.ChoiceLabel
+	default:
+		if entry.ChoiceLabel == "" {
+			return tr("no")
+		}
+		return tr("no") + ": " + entry.ChoiceLabel
+	}
+}
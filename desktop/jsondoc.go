@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// jsonObject is a decoded JSON object that keeps its key order, so deck
+// files rewritten by the deck tools keep the author's layout
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+
+	// Where the object starts in its file, when the decoder knows
+	line, column int
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{values: make(map[string]any)}
+}
+
+func (o *jsonObject) Get(key string) (any, bool) {
+	v, ok := o.values[key]
+	return v, ok
+}
+
+// Set replaces the value of a key, adding new keys at the end
+func (o *jsonObject) Set(key string, v any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = v
+}
+
+// SetFirst sets a key and moves it to the front
+func (o *jsonObject) SetFirst(key string, v any) {
+	o.Delete(key)
+	o.keys = append([]string{key}, o.keys...)
+	o.values[key] = v
+}
+
+func (o *jsonObject) Delete(key string) {
+	if _, ok := o.values[key]; !ok {
+		return
+	}
+	o.keys = slices.DeleteFunc(o.keys, func(k string) bool { return k == key })
+	delete(o.values, key)
+}
+
+// Rename gives a key a new name in the same position
+func (o *jsonObject) Rename(from, to string) {
+	v, ok := o.values[from]
+	if !ok {
+		return
+	}
+	o.Delete(to)
+	o.keys[slices.Index(o.keys, from)] = to
+	delete(o.values, from)
+	o.values[to] = v
+}
+
+// MarshalJSON writes the object compactly in key order
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decodeJSONDocument decodes JSON into *jsonObject, []any, string,
+// json.Number, bool and nil values, keeping key order and number text
+func decodeJSONDocument(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	v, err := decodeJSONValue(dec, data)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, &json.SyntaxError{Offset: dec.InputOffset()}
+	}
+	return v, nil
+}
+
+func decodeJSONValue(dec *json.Decoder, data []byte) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+	switch delim {
+	case '{':
+		obj := newJSONObject()
+		obj.line, obj.column = lineColumn(data, dec.InputOffset()-1)
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := keyTok.(string)
+			if !ok {
+				return nil, errors.New("object key is not a string")
+			}
+			v, err := decodeJSONValue(dec, data)
+			if err != nil {
+				return nil, err
+			}
+			obj.Set(key, v)
+		}
+		_, err = dec.Token()
+		return obj, err
+	case '[':
+		arr := []any{}
+		for dec.More() {
+			v, err := decodeJSONValue(dec, data)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		_, err = dec.Token()
+		return arr, err
+	}
+	return nil, fmt.Errorf("unexpected %v", delim)
+}
+
+// encodeJSONDocument writes a decoded document indented by four spaces,
+// with non-ASCII text left as is, the layout the shipped decks use
+func encodeJSONDocument(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeJSONValue(&buf, v, 0); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func writeJSONValue(buf *bytes.Buffer, v any, depth int) error {
+	indent := func(depth int) {
+		buf.WriteString(strings.Repeat("    ", depth))
+	}
+
+	switch v := v.(type) {
+	case *jsonObject:
+		if len(v.keys) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i, key := range v.keys {
+			indent(depth + 1)
+			writeJSONString(buf, key)
+			buf.WriteString(": ")
+			if err := writeJSONValue(buf, v.values[key], depth+1); err != nil {
+				return err
+			}
+			if i < len(v.keys)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		indent(depth)
+		buf.WriteByte('}')
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range v {
+			indent(depth + 1)
+			if err := writeJSONValue(buf, item, depth+1); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		indent(depth)
+		buf.WriteByte(']')
+	case string:
+		writeJSONString(buf, v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	buf.Write(bytes.TrimSuffix(out.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,120 @@
+package main
+
+// Rect is an axis-aligned rectangle in screen coordinates
+type Rect struct {
+	X, Y, Width, Height float64
+}
+
+// Contains reports whether the point is inside the rectangle
+func (r Rect) Contains(x, y float64) bool {
+	return x >= r.X && x <= r.X+r.Width &&
+		y >= r.Y && y <= r.Y+r.Height
+}
+
+// CenterX returns the horizontal center of the rectangle
+func (r Rect) CenterX() float64 {
+	return r.X + r.Width/2
+}
+
+// CenterY returns the vertical center of the rectangle
+func (r Rect) CenterY() float64 {
+	return r.Y + r.Height/2
+}
+
+// Layout holds every rectangle the game draws and hit-tests against.
+// It is recomputed whenever the window size changes so that drawing and
+// input handling always share the same geometry.
+type Layout struct {
+	Width, Height float64
+	Scale         float64
+	Landscape     bool
+
+	DayTextY      float64
+	Stats         Rect
+	StatsVertical bool
+	Card          Rect
+	AboutButton   Rect
+	SoundButton   Rect
+	SettingsBtn   Rect
+	UndoButton    Rect
+	RestartButton Rect
+	CopyrightY    float64
+}
+
+// Reference sizes the layout is designed against, in unscaled units
+const (
+	layoutCardWidth     = 400.0
+	layoutCardHeight    = 500.0
+	layoutStatsLength   = 280.0
+	layoutStatsDepth    = 65.0
+	layoutLandscapeW    = 900.0
+	layoutLandscapeH    = 700.0
+	landscapeAspectRate = 1.1
+)
+
+// computeLayout builds the layout for a window of the given size
+func computeLayout(width, height float64) Layout {
+	l := Layout{
+		Width:     width,
+		Height:    height,
+		Landscape: width/height > landscapeAspectRate,
+	}
+
+	if l.Landscape {
+		// Stats sit in a column on the left of the card
+		l.Scale = min(width/layoutLandscapeW, height/layoutLandscapeH)
+		s := l.Scale
+
+		cardW := layoutCardWidth * s
+		cardH := layoutCardHeight * s
+		statsW := layoutStatsDepth * s
+		statsH := layoutStatsLength * s
+		gap := 40 * s
+
+		// Center card and stats column together
+		groupW := statsW + gap + cardW
+		groupX := (width - groupW) / 2
+		cardY := (height-cardH)/2 + 20*s
+
+		l.StatsVertical = true
+		l.Stats = Rect{X: groupX, Y: cardY + (cardH-statsH)/2, Width: statsW, Height: statsH}
+		l.Card = Rect{X: groupX + statsW + gap, Y: cardY, Width: cardW, Height: cardH}
+		l.DayTextY = cardY - 25*s
+	} else {
+		// Stats sit in a row above the card
+		l.Scale = min(width/screenWidth, height/screenHeight)
+		s := l.Scale
+
+		cardW := layoutCardWidth * s
+		cardH := layoutCardHeight * s
+		statsW := layoutStatsLength * s
+		statsH := layoutStatsDepth * s
+
+		// Keep the reference design centered in the window
+		offsetY := (height - screenHeight*s) / 2
+
+		l.DayTextY = offsetY + 50*s
+		l.Stats = Rect{X: (width - statsW) / 2, Y: offsetY + 70*s, Width: statsW, Height: statsH}
+		l.Card = Rect{X: (width - cardW) / 2, Y: (height-cardH)/2 + 50*s, Width: cardW, Height: cardH}
+	}
+
+	s := l.Scale
+	l.AboutButton = Rect{X: width - 45*s, Y: 15 * s, Width: 30 * s, Height: 30 * s}
+	l.SoundButton = Rect{X: width - 85*s, Y: 15 * s, Width: 30 * s, Height: 30 * s}
+	l.SettingsBtn = Rect{X: width - 125*s, Y: 15 * s, Width: 30 * s, Height: 30 * s}
+	l.UndoButton = Rect{X: 15 * s, Y: 15 * s, Width: 60 * s, Height: 30 * s}
+	l.RestartButton = Rect{X: width/2 - 80*s, Y: height/2 + 75*s, Width: 160 * s, Height: 50 * s}
+	l.CopyrightY = height - 10*s
+
+	return l
+}
+
+// setLayout applies a new layout and moves the buttons to match it
+func (g *Game) setLayout(l Layout) {
+	g.layout = l
+	g.restartButton.setRect(l.RestartButton)
+	g.aboutButton.setRect(l.AboutButton)
+	g.soundButton.setRect(l.SoundButton)
+	g.settingsButton.setRect(l.SettingsBtn)
+	g.undoButton.setRect(l.UndoButton)
+}
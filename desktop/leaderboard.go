@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// How many runs each leaderboard shows
+const leaderboardSize = 10
+
+// leaderboardEntry is one ranked run
+type leaderboardEntry struct {
+	Profile string
+	Run     RunRecord
+	Score   int
+}
+
+// leaderboardBoard is one ranked list, for a mode or a difficulty
+type leaderboardBoard struct {
+	Title   string
+	Entries []leaderboardEntry
+}
+
+// runScore rewards surviving, keeping the stats high and winning
+func runScore(run RunRecord) int {
+	score := run.Days*10 + (run.Final.Motivation+run.Final.Performance+run.Final.Colleagues+run.Final.Boss)/4
+	if run.Won {
+		score += 100
+	}
+	return score
+}
+
+// buildLeaderboards ranks the runs of every profile, by days survived and
+// then by score. There is one board per mode and one per difficulty.
+func (g *Game) buildLeaderboards() []leaderboardBoard {
+	var entries []leaderboardEntry
+	for _, profile := range g.profiles.Profiles {
+		store := &StatsStore{}
+		if err := loadUserJSON(filepath.Join(profilesDir, profile.ID, statsFile), store); err != nil {
+			log.Printf("Failed to load stats of profile %s: %v", profile.ID, err)
+			continue
+		}
+		for _, run := range store.Runs {
+			entries = append(entries, leaderboardEntry{Profile: profile.Name, Run: run, Score: runScore(run)})
+		}
+	}
+
+	// Runs recorded before modes existed were standard runs
+	mode := func(run RunRecord) string {
+		if run.Mode == "" {
+			return modeStandard
+		}
+		return run.Mode
+	}
+	difficulty := func(run RunRecord) string {
+		if run.Difficulty == "" {
+			return difficultyNormal
+		}
+		return run.Difficulty
+	}
+
+	boards := []leaderboardBoard{
+		rankRuns(tr("leaderboard.standard"), entries, func(run RunRecord) bool { return mode(run) == modeStandard }),
+		rankRuns(tr("daily.title"), entries, func(run RunRecord) bool { return mode(run) == modeDaily }),
+		rankRuns(tr("casual.title"), entries, func(run RunRecord) bool { return mode(run) == modeCasual }),
+	}
+
+	// One board per difficulty that has been played
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		d := difficulty(entry.Run)
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+		boards = append(boards, rankRuns(fmt.Sprintf(tr("leaderboard.difficulty"), tr("difficulty."+d)), entries,
+			func(run RunRecord) bool { return difficulty(run) == d }))
+	}
+	return boards
+}
+
+// rankRuns keeps the matching runs, best first
+func rankRuns(title string, entries []leaderboardEntry, match func(run RunRecord) bool) leaderboardBoard {
+	board := leaderboardBoard{Title: title}
+	for _, entry := range entries {
+		if match(entry.Run) {
+			board.Entries = append(board.Entries, entry)
+		}
+	}
+
+	sort.SliceStable(board.Entries, func(i, j int) bool {
+		a, b := board.Entries[i], board.Entries[j]
+		if a.Run.Days != b.Run.Days {
+			return a.Run.Days > b.Run.Days
+		}
+		return a.Score > b.Score
+	})
+	if len(board.Entries) > leaderboardSize {
+		board.Entries = board.Entries[:leaderboardSize]
+	}
+	return board
+}
+
+// leaderboardGeometry lays out the panel and the tab buttons
+func (g *Game) leaderboardGeometry(boards int) (panel Rect, tabs []Rect) {
+	l := g.layout
+	s := l.Scale
+
+	width := min(520*s, l.Width-20*s)
+	height := min(560*s, l.Height-20*s)
+	panel = Rect{X: (l.Width - width) / 2, Y: (l.Height - height) / 2, Width: width, Height: height}
+
+	tabWidth := (width - 40*s) / float64(max(boards, 1))
+	for i := 0; i < boards; i++ {
+		tabs = append(tabs, Rect{X: panel.X + 20*s + float64(i)*tabWidth, Y: panel.Y + 65*s, Width: tabWidth - 4*s, Height: 34 * s})
+	}
+	return panel, tabs
+}
+
+// openLeaderboard ranks the runs once when the screen is opened
+func (g *Game) openLeaderboard() {
+	g.leaderboards = g.buildLeaderboards()
+	g.leaderboardTab = 0
+}
+
+// updateLeaderboard handles switching tabs and closing the screen
+func (g *Game) updateLeaderboard() {
+	if g.leaderboards == nil {
+		g.openLeaderboard()
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.leaderboards = nil
+		g.returnToGame()
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+		g.leaderboardTab = wrapIndex(g.leaderboardTab+1, len(g.leaderboards))
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+		g.leaderboardTab = wrapIndex(g.leaderboardTab-1, len(g.leaderboards))
+	}
+
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+
+	mx, my := ebiten.CursorPosition()
+	x, y := float64(mx), float64(my)
+	panel, tabs := g.leaderboardGeometry(len(g.leaderboards))
+	for i, tab := range tabs {
+		if tab.Contains(x, y) {
+			g.leaderboardTab = i
+			return
+		}
+	}
+
+	// Click outside the panel closes the screen
+	if !panel.Contains(x, y) {
+		g.leaderboards = nil
+		g.returnToGame()
+	}
+}
+
+func (g *Game) drawLeaderboardScreen(screen *ebiten.Image) {
+	if g.leaderboards == nil {
+		g.openLeaderboard()
+	}
+
+	l := g.layout
+	s := l.Scale
+	panel, tabs := g.leaderboardGeometry(len(g.leaderboards))
+
+	// Draw overlay and panel
+	drawOverlay(screen, l)
+	vector.DrawFilledRect(screen, float32(panel.X), float32(panel.Y), float32(panel.Width), float32(panel.Height), colorPanel, true)
+
+	// Title
+	title := tr("leaderboard.title")
+	w, _ := getBoundsSize(boldFont, title)
+	drawTextWithOptions(screen, title, boldFont, int(panel.CenterX())-w/2, int(panel.Y+45*s), colorTextLight)
+
+	// Tabs
+	for i, tab := range tabs {
+		clr := colorAboutBtn
+		if i == g.leaderboardTab {
+			clr = colorRestartBtn
+		}
+		g.drawButton(screen, Button{
+			X: tab.X, Y: tab.Y, Width: tab.Width, Height: tab.Height,
+			Text: firstLine(g.leaderboards[i].Title, smallFont, int(tab.Width-8*s)), Color: clr, TextColor: colorTextLight,
+		})
+	}
+
+	board := g.leaderboards[g.leaderboardTab]
+	x := int(panel.X + 30*s)
+	y := int(panel.Y + 130*s)
+	if len(board.Entries) == 0 {
+		drawWrappedText(screen, tr("leaderboard.empty"), regularFont, x, y, int(panel.Width-60*s), colorTextLight)
+		return
+	}
+
+	// Rank, profile, days and score. Runs that used undo are starred.
+	lineHeight := lineHeightFor(regularFont)
+	usedUndo := false
+	for i, entry := range board.Entries {
+		row := y + i*lineHeight
+		name := fmt.Sprintf("%d. %s", i+1, entry.Profile)
+		if entry.Run.Undos > 0 {
+			name += " *"
+			usedUndo = true
+		}
+		drawTextWithOptions(screen, firstLine(name, regularFont, int(panel.Width*0.45)), regularFont, x, row, colorTextLight)
+
+		result := fmt.Sprintf(tr("leaderboard.result"), entry.Run.Days, entry.Score)
+		w, _ := getBoundsSize(regularFont, result)
+		drawTextWithOptions(screen, result, regularFont, int(panel.X+panel.Width-30*s)-w, row, colorTextLight)
+	}
+
+	if usedUndo {
+		drawTextWithOptions(screen, tr("leaderboard.undoNote"), smallFont,
+			x, y+len(board.Entries)*lineHeight+int(10*s), colorTextLight)
+	}
+}
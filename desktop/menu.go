@@ -0,0 +1,103 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// menuEntry opens one of the secondary screens
+type menuEntry struct {
+	label string
+	state int
+}
+
+var menuEntries = []menuEntry{
+	{label: "journal.title", state: stateJournal},
+	{label: "daily.title", state: stateDaily},
+	{label: "leaderboard.title", state: stateLeaderboard},
+	{label: "profiles.title", state: stateProfiles},
+	{label: "packs.title", state: statePacks},
+	{label: "settings.title", state: stateSettings},
+	{label: "stats.title", state: stateStats},
+	{label: "gallery.title", state: stateGallery},
+	{label: "achievements.title", state: stateAchievements},
+}
+
+// menuGeometry lays out the menu panel and its buttons
+func (g *Game) menuGeometry() (panel Rect, buttons []Rect) {
+	l := g.layout
+	s := l.Scale
+	buttonWidth := 260 * s
+	buttonHeight := 44 * s
+	spacing := 12 * s
+
+	height := 80*s + float64(len(menuEntries))*(buttonHeight+spacing) + 20*s
+	panel = Rect{X: (l.Width - 320*s) / 2, Y: (l.Height - height) / 2, Width: 320 * s, Height: height}
+
+	y := panel.Y + 70*s
+	for range menuEntries {
+		buttons = append(buttons, Rect{X: panel.CenterX() - buttonWidth/2, Y: y, Width: buttonWidth, Height: buttonHeight})
+		y += buttonHeight + spacing
+	}
+	return panel, buttons
+}
+
+// updateMenu handles input on the menu
+func (g *Game) updateMenu() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.returnToGame()
+		return
+	}
+
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+
+	mx, my := ebiten.CursorPosition()
+	x, y := float64(mx), float64(my)
+	panel, buttons := g.menuGeometry()
+
+	for i, button := range buttons {
+		if button.Contains(x, y) {
+			g.state = menuEntries[i].state
+			return
+		}
+	}
+
+	// Click outside the panel closes the menu
+	if !panel.Contains(x, y) {
+		g.returnToGame()
+	}
+}
+
+// updateInfoScreen closes a read-only screen on click or Escape
+func (g *Game) updateInfoScreen() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		g.returnToGame()
+	}
+}
+
+func (g *Game) drawMenuScreen(screen *ebiten.Image) {
+	l := g.layout
+	s := l.Scale
+	panel, buttons := g.menuGeometry()
+
+	// Draw overlay and panel
+	drawOverlay(screen, l)
+	vector.DrawFilledRect(screen, float32(panel.X), float32(panel.Y), float32(panel.Width), float32(panel.Height), colorPanel, true)
+
+	// Title
+	title := tr("menu.title") + " · " + g.profiles.Find(activeProfileID).Name
+	w, _ := getBoundsSize(boldFont, title)
+	drawTextWithOptions(screen, title, boldFont, int(panel.CenterX())-w/2, int(panel.Y+45*s), colorTextLight)
+
+	mx, my := ebiten.CursorPosition()
+	for i, button := range buttons {
+		g.drawButton(screen, Button{
+			X: button.X, Y: button.Y, Width: button.Width, Height: button.Height,
+			Text: tr(menuEntries[i].label), Color: colorRestartBtn, HoverColor: colorRestartHover, TextColor: colorTextLight,
+			IsHovered: button.Contains(float64(mx), float64(my)),
+		})
+	}
+}
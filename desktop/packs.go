@@ -0,0 +1,709 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	// Asset directory next to the base deck holding one subdirectory per pack
+	packsDir         = "packs"
+	packManifestFile = "pack.json"
+	packDeckName     = "deck"
+
+	// Separates a pack ID from the IDs of its cards, characters and
+	// achievements
+	packSeparator = ":"
+)
+
+// PackManifest describes a deck pack
+type PackManifest struct {
+	ID           string   `json:"id"` // Defaults to the directory name
+	Name         string   `json:"name"`
+	Version      string   `json:"version"`
+	Author       string   `json:"author"`
+	Description  string   `json:"description,omitempty"`
+	Dependencies []string `json:"dependencies,omitempty"` // IDs of packs that must be enabled and load first
+}
+
+// PackDeck is the deck file of a pack. New content is namespaced with the
+// pack ID; Override, Patch and Remove change cards of the base deck or of
+// packs loaded earlier, followup cards included, addressed by their full
+// IDs.
+type PackDeck struct {
+	Deck
+	Override []*Card           `json:"override,omitempty"` // Replaces every card with the same ID
+	Patch    []json.RawMessage `json:"patch,omitempty"`    // Replaces only the listed fields, null removes one
+	Remove   []string          `json:"remove,omitempty"`   // Removes every card with the ID, followups included
+}
+
+// Pack is a pack found in the content directory
+type Pack struct {
+	Manifest PackManifest
+	Dir      string // Relative to the deck directory, slash separated
+	Err      error  // Why the manifest couldn't be read
+}
+
+// discoverPacks lists the packs in the content directory, sorted by ID
+func discoverPacks(deckDir string) []*Pack {
+	entries, err := fs.ReadDir(assets, path.Join(deckDir, packsDir))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Failed to list packs: %v", err)
+		}
+		return nil
+	}
+
+	var packs []*Pack
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pack := &Pack{Dir: path.Join(packsDir, entry.Name())}
+		data, err := fs.ReadFile(assets, path.Join(deckDir, pack.Dir, packManifestFile))
+		if err == nil {
+			if err = json.Unmarshal(data, &pack.Manifest); err != nil {
+				err = positionedError(data, err)
+			}
+		}
+		if pack.Manifest.ID == "" {
+			pack.Manifest.ID = entry.Name()
+		}
+		if pack.Manifest.Name == "" {
+			pack.Manifest.Name = pack.Manifest.ID
+		}
+		if err == nil && strings.Contains(pack.Manifest.ID, packSeparator) {
+			err = fmt.Errorf("pack ID %q must not contain %q", pack.Manifest.ID, packSeparator)
+		}
+		pack.Err = err
+		packs = append(packs, pack)
+	}
+
+	sort.Slice(packs, func(i, j int) bool {
+		return packs[i].Manifest.ID < packs[j].Manifest.ID
+	})
+	return packs
+}
+
+// packOrder returns the enabled packs with every pack after its
+// dependencies, skipping packs whose dependencies can't be met
+func packOrder(packs []*Pack, enabled []string) (order []*Pack, report []string) {
+	installed := make(map[string]*Pack, len(packs))
+	for _, pack := range packs {
+		installed[pack.Manifest.ID] = pack
+	}
+
+	const (
+		visiting = iota + 1
+		loaded
+		skipped
+	)
+	status := make(map[string]int)
+
+	var visit func(pack *Pack) bool
+	visit = func(pack *Pack) bool {
+		id := pack.Manifest.ID
+		switch status[id] {
+		case visiting:
+			report = append(report, fmt.Sprintf("%s: dependency cycle", id))
+			return false
+		case loaded:
+			return true
+		case skipped:
+			return false
+		}
+
+		status[id] = visiting
+		for _, dep := range pack.Manifest.Dependencies {
+			depPack := installed[dep]
+			var problem string
+			switch {
+			case depPack == nil:
+				problem = "is not installed"
+			case !slices.Contains(enabled, dep):
+				problem = "is disabled"
+			case depPack.Err != nil:
+				problem = "is broken"
+			case !visit(depPack):
+				problem = "was skipped"
+			}
+			if problem != "" {
+				report = append(report, fmt.Sprintf("%s: skipped, dependency %s %s", id, dep, problem))
+				status[id] = skipped
+				return false
+			}
+		}
+		status[id] = loaded
+		order = append(order, pack)
+		return true
+	}
+
+	for _, pack := range packs {
+		if !slices.Contains(enabled, pack.Manifest.ID) {
+			continue
+		}
+		if pack.Err != nil {
+			report = append(report, fmt.Sprintf("%s: skipped, %v", pack.Manifest.ID, pack.Err))
+			continue
+		}
+		visit(pack)
+	}
+	return order, report
+}
+
+// mergePacks applies the enabled packs to the base deck in load order. It
+// returns the pack files that went into the deck, for its fingerprint,
+// and a report of skipped packs, missing targets and cards changed by
+// more than one pack.
+func mergePacks(deck *Deck, deckDir string, packs []*Pack, enabled []string) (data []byte, report []string) {
+	order, report := packOrder(packs, enabled)
+
+	// Which packs changed each card, to report load-order conflicts
+	changedBy := make(map[string][]string)
+
+	for _, pack := range order {
+		packFile := findDeckFile(assets, path.Join(deckDir, pack.Dir), packDeckName)
+		packData, err := fs.ReadFile(assets, packFile)
+		if err != nil {
+			report = append(report, fmt.Sprintf("%s: skipped, %v", pack.Manifest.ID, err))
+			continue
+		}
+		var packDeck PackDeck
+		doc, _, _, err := decodeDeckFile(packFile, packData, &packDeck)
+		if err != nil {
+			report = append(report, fmt.Sprintf("%s: skipped, %s: %v", pack.Manifest.ID, path.Base(packFile), err))
+			continue
+		}
+		packDeck.recordSources(packFile, doc)
+		if len(packDeck.Include) > 0 {
+			report = append(report, fmt.Sprintf("%s: include only works in the base deck, ignored", pack.Manifest.ID))
+		}
+
+		// Merge into a copy, so a pack that breaks the deck leaves no trace
+		merged := deck.clone()
+		changed := make(map[string][]string)
+		packReport := applyPack(merged, pack, &packDeck, changed)
+		if err := errors.Join(resolveFollowupRefs(merged), validateDeck(merged)); err != nil {
+			for _, line := range strings.Split(err.Error(), "\n") {
+				report = append(report, fmt.Sprintf("%s: skipped, %s", pack.Manifest.ID, line))
+			}
+			continue
+		}
+		*deck = *merged
+		for id, changes := range changed {
+			changedBy[id] = append(changedBy[id], changes...)
+		}
+
+		data = append(data, pack.Manifest.ID...)
+		data = append(data, packData...)
+		report = append(report, packReport...)
+	}
+
+	targets := make([]string, 0, len(changedBy))
+	for id, changes := range changedBy {
+		if len(changes) > 1 {
+			targets = append(targets, id)
+		}
+	}
+	sort.Strings(targets)
+	for _, id := range targets {
+		changes := changedBy[id]
+		report = append(report, fmt.Sprintf("%s: changed by %s; %s applies last", id, strings.Join(changes, ", "), changes[len(changes)-1]))
+	}
+	return data, report
+}
+
+// clone copies the deck deep enough for a pack to change its cards without
+// touching the original, keeping where each card is defined
+func (d *Deck) clone() *Deck {
+	c := *d
+	c.sources = make(map[any]sourcePos, len(d.sources))
+	for item, pos := range d.sources {
+		c.sources[item] = pos
+	}
+
+	var cloneCards func(cards []*Card) []*Card
+	cloneCards = func(cards []*Card) []*Card {
+		if cards == nil {
+			return nil
+		}
+		list := make([]*Card, len(cards))
+		for i, card := range cards {
+			copied := *card
+			copied.YesFollowups = cloneCards(card.YesFollowups)
+			copied.NoFollowups = cloneCards(card.NoFollowups)
+			copied.Followups = cloneCards(card.Followups)
+			if pos, ok := d.sources[card]; ok {
+				c.sources[&copied] = pos
+			}
+			list[i] = &copied
+		}
+		return list
+	}
+	c.Cards = cloneCards(d.Cards)
+	c.FollowupCards = cloneCards(d.FollowupCards)
+	c.Characters = slices.Clone(d.Characters)
+	c.Achievements = slices.Clone(d.Achievements)
+	return &c
+}
+
+// applyPack merges one pack into the deck
+func applyPack(deck *Deck, pack *Pack, packDeck *PackDeck, changedBy map[string][]string) (report []string) {
+	id := pack.Manifest.ID
+	ns := newPackNamespace(pack, packDeck)
+
+	for _, card := range packDeck.Override {
+		ns.localizeCard(card, false)
+		matches := findCards(deck.definedCards(), card.ID)
+		if len(matches) == 0 {
+			report = append(report, fmt.Sprintf("%s: override target %s not found", id, card.ID))
+			continue
+		}
+		for _, match := range matches {
+			replacement := *card
+			*match = replacement
+		}
+		changedBy[card.ID] = append(changedBy[card.ID], id+" (override)")
+	}
+
+	for _, raw := range packDeck.Patch {
+		target, err := ns.patchCards(deck, raw)
+		if err != nil {
+			report = append(report, fmt.Sprintf("%s: %v", id, err))
+			continue
+		}
+		changedBy[target] = append(changedBy[target], id+" (patch)")
+	}
+
+	for _, target := range packDeck.Remove {
+		if removeCards(&deck.Cards, target)+removeCards(&deck.FollowupCards, target) == 0 {
+			report = append(report, fmt.Sprintf("%s: remove target %s not found", id, target))
+			continue
+		}
+		changedBy[target] = append(changedBy[target], id+" (remove)")
+	}
+
+	// New content, refs are resolved once the pack is in
+	for _, card := range packDeck.definedCards() {
+		ns.localizeCard(card, true)
+	}
+	for _, character := range packDeck.Characters {
+		character.ID = ns.qualify(character.ID)
+		character.Portrait = ns.assetPath(character.Portrait)
+	}
+	for _, achievement := range packDeck.Achievements {
+		achievement.ID = ns.qualify(achievement.ID)
+		ns.localizeCondition(&achievement.Condition)
+	}
+	deck.Cards = append(deck.Cards, packDeck.Cards...)
+	deck.FollowupCards = append(deck.FollowupCards, packDeck.FollowupCards...)
+	deck.Characters = append(deck.Characters, packDeck.Characters...)
+	deck.Achievements = append(deck.Achievements, packDeck.Achievements...)
+	for item, pos := range packDeck.sources {
+		deck.sources[item] = pos
+	}
+	return report
+}
+
+// packNamespace rewrites a pack's own IDs and asset paths
+type packNamespace struct {
+	pack       *Pack
+	cards      map[string]bool // IDs of the pack's new cards
+	characters map[string]bool // IDs of the pack's new characters
+}
+
+func newPackNamespace(pack *Pack, packDeck *PackDeck) *packNamespace {
+	ns := &packNamespace{pack: pack, cards: make(map[string]bool), characters: make(map[string]bool)}
+	forEachCard(packDeck.definedCards(), func(card *Card) {
+		ns.cards[card.ID] = true
+	})
+	for _, character := range packDeck.Characters {
+		ns.characters[character.ID] = true
+	}
+	return ns
+}
+
+func (ns *packNamespace) qualify(id string) string {
+	return ns.pack.Manifest.ID + packSeparator + id
+}
+
+// assetPath makes a path relative to the pack directory relative to the
+// deck directory
+func (ns *packNamespace) assetPath(name string) string {
+	if name == "" {
+		return ""
+	}
+	return path.Join(ns.pack.Dir, name)
+}
+
+// localizeCard namespaces a card and its followups. Overrides keep the ID
+// of the card they replace, and refs to cards outside the pack point at
+// the base deck.
+func (ns *packNamespace) localizeCard(card *Card, rename bool) {
+	localize := func(c *Card) {
+		if ns.characters[c.Speaker] {
+			c.Speaker = ns.qualify(c.Speaker)
+		}
+		c.Image = ns.assetPath(c.Image)
+		c.Sound = ns.assetPath(c.Sound)
+	}
+
+	localize(card)
+	if rename {
+		card.ID = ns.qualify(card.ID)
+	}
+	for _, list := range [][]*Card{card.YesFollowups, card.NoFollowups, card.Followups} {
+		forEachCard(list, func(c *Card) {
+			if c.Ref != "" {
+				if ns.cards[c.Ref] {
+					c.Ref = ns.qualify(c.Ref)
+				}
+				return
+			}
+			localize(c)
+			c.ID = ns.qualify(c.ID)
+		})
+	}
+}
+
+// localizeCondition points achievement conditions at the pack's own cards
+// and characters
+func (ns *packNamespace) localizeCondition(condition *AchievementCondition) {
+	for i, id := range condition.Cards {
+		if ns.cards[id] {
+			condition.Cards[i] = ns.qualify(id)
+		}
+	}
+	if ns.characters[condition.Speaker] {
+		condition.Speaker = ns.qualify(condition.Speaker)
+	}
+	for i := range condition.Conditions {
+		ns.localizeCondition(&condition.Conditions[i])
+	}
+}
+
+// patchCards sets the fields given in a patch on every card with its ID
+// and returns that ID
+func (ns *packNamespace) patchCards(deck *Deck, raw json.RawMessage) (string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return "", fmt.Errorf("patch: %v", err)
+	}
+	var target string
+	if err := json.Unmarshal(fields["id"], &target); err != nil || target == "" {
+		return "", fmt.Errorf("patch without an id")
+	}
+
+	matches := findCards(deck.definedCards(), target)
+	if len(matches) == 0 {
+		return "", fmt.Errorf("patch target %s not found", target)
+	}
+
+	// Followups set by the patch get the defaults of followups written
+	// anywhere else
+	for _, keys := range singularFollowups {
+		value, ok := fields[keys[1]]
+		if !ok || string(value) == "null" {
+			continue
+		}
+		doc, err := decodeJSONDocument(value)
+		if err != nil {
+			return "", fmt.Errorf("patch %s: %v", target, err)
+		}
+		list, _ := doc.([]any)
+		applyCardDefaults(list, true)
+		if fields[keys[1]], err = json.Marshal(doc); err != nil {
+			return "", err
+		}
+	}
+
+	for _, match := range matches {
+		original, err := json.Marshal(match)
+		if err != nil {
+			return "", err
+		}
+		var merged map[string]json.RawMessage
+		if err := json.Unmarshal(original, &merged); err != nil {
+			return "", err
+		}
+		for key, value := range fields {
+			if string(value) == "null" {
+				delete(merged, key)
+			} else {
+				merged[key] = value
+			}
+		}
+		mergedData, err := json.Marshal(merged)
+		if err != nil {
+			return "", err
+		}
+		var patched Card
+		if err := json.Unmarshal(mergedData, &patched); err != nil {
+			return "", fmt.Errorf("patch %s: %v", target, err)
+		}
+
+		// Fields coming from the pack resolve against the pack
+		if _, ok := fields["image"]; ok {
+			patched.Image = ns.assetPath(patched.Image)
+		}
+		if _, ok := fields["sound"]; ok {
+			patched.Sound = ns.assetPath(patched.Sound)
+		}
+		if _, ok := fields["speaker"]; ok && ns.characters[patched.Speaker] {
+			patched.Speaker = ns.qualify(patched.Speaker)
+		}
+		for key, list := range map[string][]*Card{"yesFollowups": patched.YesFollowups, "noFollowups": patched.NoFollowups, "followups": patched.Followups} {
+			if _, ok := fields[key]; ok {
+				for _, card := range list {
+					ns.localizeCard(card, true)
+				}
+			}
+		}
+		*match = patched
+	}
+	return target, nil
+}
+
+// findCards returns every card with the ID, followups included
+func findCards(cards []*Card, id string) []*Card {
+	var matches []*Card
+	forEachCard(cards, func(card *Card) {
+		if card.ID == id {
+			matches = append(matches, card)
+		}
+	})
+	return matches
+}
+
+// removeCards deletes every card with the ID from the list and from the
+// followups of the cards left, and returns how many were removed
+func removeCards(cards *[]*Card, id string) int {
+	removed := 0
+	kept := (*cards)[:0]
+	for _, card := range *cards {
+		if card.ID == id {
+			removed++
+			continue
+		}
+		removed += removeCards(&card.YesFollowups, id)
+		removed += removeCards(&card.NoFollowups, id)
+		removed += removeCards(&card.Followups, id)
+		kept = append(kept, card)
+	}
+	*cards = kept
+	return removed
+}
+
+// buildDeck reads the base deck and merges the enabled packs into it
+func (g *Game) buildDeck(filename string) (*Deck, string, error) {
+	deck, data, watch, err := readDeck(assets, filename)
+	g.deckWatch = watch
+	if err != nil {
+		return nil, "", err
+	}
+
+	deckDir := path.Dir(filename)
+	g.packs = discoverPacks(deckDir)
+	packData, report := mergePacks(deck, deckDir, g.packs, g.settings.Packs)
+	g.packReport = report
+	for _, line := range report {
+		log.Printf("Pack: %s", line)
+	}
+	return deck, hashDeck(append(data, packData...)), nil
+}
+
+// deckModTime returns when the deck files, include directories or any
+// pack last changed. The first file is the deck root.
+func deckModTime(watch []string) time.Time {
+	var latest time.Time
+	check := func(name string) {
+		if info, err := fs.Stat(assets, name); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	if len(watch) == 0 {
+		return latest
+	}
+	for _, name := range watch {
+		check(name)
+	}
+	dir := path.Join(path.Dir(watch[0]), packsDir)
+	entries, _ := fs.ReadDir(assets, dir)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			check(path.Join(dir, entry.Name(), packManifestFile))
+			check(findDeckFile(assets, path.Join(dir, entry.Name()), packDeckName))
+		}
+	}
+	return latest
+}
+
+// packsGeometry lays out the packs panel, one row per pack, with room for
+// the load report below
+func (g *Game) packsGeometry() (panel Rect, rows []Rect) {
+	l := g.layout
+	s := l.Scale
+	rowHeight := 52 * s
+	spacing := 8 * s
+
+	width := min(480*s, l.Width-20*s)
+	height := min(80*s+float64(max(len(g.packs), 1))*(rowHeight+spacing)+180*s, l.Height-20*s)
+	panel = Rect{X: (l.Width - width) / 2, Y: (l.Height - height) / 2, Width: width, Height: height}
+
+	y := panel.Y + 70*s
+	for range g.packs {
+		rows = append(rows, Rect{X: panel.X + 20*s, Y: y, Width: width - 40*s, Height: rowHeight})
+		y += rowHeight + spacing
+	}
+	return panel, rows
+}
+
+// updatePacks toggles packs. The deck is rebuilt on every change so the
+// report stays current, and the run restarts when the screen is closed.
+func (g *Game) updatePacks() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.closePacks()
+		return
+	}
+
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+
+	mx, my := ebiten.CursorPosition()
+	x, y := float64(mx), float64(my)
+	panel, rows := g.packsGeometry()
+
+	for i, row := range rows {
+		if row.Contains(x, y) {
+			g.togglePack(g.packs[i])
+			return
+		}
+	}
+
+	// Click outside the panel closes the screen
+	if !panel.Contains(x, y) {
+		g.closePacks()
+	}
+}
+
+func (g *Game) togglePack(pack *Pack) {
+	if pack.Err != nil {
+		return
+	}
+
+	id := pack.Manifest.ID
+	if i := slices.Index(g.settings.Packs, id); i >= 0 {
+		g.settings.Packs = slices.Delete(g.settings.Packs, i, i+1)
+	} else {
+		g.settings.Packs = append(g.settings.Packs, id)
+		sort.Strings(g.settings.Packs)
+	}
+	g.saveSettings()
+	g.packsChanged = true
+	g.loadCards(g.deckFile)
+}
+
+func (g *Game) closePacks() {
+	if g.packsChanged {
+		g.packsChanged = false
+		g.restartGame()
+		return
+	}
+	g.returnToGame()
+}
+
+func (g *Game) drawPacksScreen(screen *ebiten.Image) {
+	l := g.layout
+	s := l.Scale
+	panel, rows := g.packsGeometry()
+
+	// Draw overlay and panel
+	drawOverlay(screen, l)
+	vector.DrawFilledRect(screen, float32(panel.X), float32(panel.Y), float32(panel.Width), float32(panel.Height), colorPanel, true)
+
+	// Title
+	title := tr("packs.title")
+	w, _ := getBoundsSize(boldFont, title)
+	drawTextWithOptions(screen, title, boldFont, int(panel.CenterX())-w/2, int(panel.Y+45*s), colorTextLight)
+
+	if len(g.packs) == 0 {
+		dir := packsDir
+		if len(assetDirs) > 0 {
+			dir = filepath.Join(assetDirs[0], filepath.FromSlash(path.Join(g.deckDir, packsDir)))
+		}
+		drawWrappedText(screen, fmt.Sprintf(tr("packs.empty"), dir), regularFont,
+			int(panel.X+30*s), int(panel.Y+100*s), int(panel.Width-60*s), colorTextLight)
+		return
+	}
+
+	// Packs, enabled ones highlighted and broken ones in red
+	mx, my := ebiten.CursorPosition()
+	for i, row := range rows {
+		pack := g.packs[i]
+		m := pack.Manifest
+		enabled := slices.Contains(g.settings.Packs, m.ID)
+
+		clr := colorAboutBtn
+		status := tr("off")
+		switch {
+		case pack.Err != nil:
+			clr = colorNoOption
+			status = tr("packs.broken")
+		case enabled:
+			clr = colorRestartBtn
+			status = tr("on")
+		}
+		g.drawButton(screen, Button{
+			X: row.X, Y: row.Y, Width: row.Width, Height: row.Height,
+			Color: clr, HoverColor: colorRestartHover, TextColor: colorTextLight,
+			IsHovered: pack.Err == nil && row.Contains(float64(mx), float64(my)),
+		})
+
+		name := fmt.Sprintf("%s %s", m.Name, m.Version)
+		drawTextWithOptions(screen, firstLine(name, regularFont, int(row.Width-100*s)), regularFont,
+			int(row.X+12*s), int(row.Y+22*s), colorTextLight)
+		details := fmt.Sprintf(tr("packs.details"), m.Author)
+		if len(m.Dependencies) > 0 {
+			details += " · " + fmt.Sprintf(tr("packs.dependencies"), strings.Join(m.Dependencies, ", "))
+		}
+		drawTextWithOptions(screen, firstLine(details, smallFont, int(row.Width-100*s)), smallFont,
+			int(row.X+12*s), int(row.Y+42*s), colorTextLight)
+		sw, _ := getBoundsSize(regularFont, status)
+		drawTextWithOptions(screen, status, regularFont, int(row.X+row.Width-12*s)-sw, int(row.Y+32*s), colorTextLight)
+	}
+
+	// Load report under the list
+	y := panel.Y + 70*s
+	if len(rows) > 0 {
+		last := rows[len(rows)-1]
+		y = last.Y + last.Height + 24*s
+	}
+	report := tr("packs.noConflicts")
+	if len(g.packReport) > 0 {
+		report = tr("packs.report") + "\n" + strings.Join(g.packReport, "\n")
+	}
+	lines := wrapText(report, smallFont, int(panel.Width-60*s))
+	lineHeight := lineHeightFor(smallFont)
+	for i, line := range lines {
+		lineY := y + float64(i*lineHeight)
+		if lineY > panel.Y+panel.Height-10*s {
+			break
+		}
+		drawTextWithOptions(screen, line, smallFont, int(panel.X+30*s), int(lineY), colorTextLight)
+	}
+}
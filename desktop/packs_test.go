@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// Base deck the packs below change. G is a followup card nothing refers to.
+const packTestDeck = `{
+	"schemaVersion": 2,
+	"cards": [
+		{"id": "A", "text": "a", "yesText": "keep", "image": "a.png", "yesFollowups": [{"ref": "F"}]},
+		{"id": "B", "text": "b"},
+		{"id": "C", "text": "c"}
+	],
+	"followupCards": [
+		{"id": "F", "text": "f"},
+		{"id": "G", "text": "g"}
+	]
+}`
+
+// packFiles returns the manifest and deck file of a pack
+func packFiles(id, deck string, dependencies ...string) map[string]string {
+	deps := ""
+	if len(dependencies) > 0 {
+		deps = fmt.Sprintf(`, "dependencies": ["%s"]`, strings.Join(dependencies, `", "`))
+	}
+	return map[string]string{
+		"packs/" + id + "/pack.json": fmt.Sprintf(`{"id": "%s", "name": "%s", "version": "1"%s}`, id, id, deps),
+		"packs/" + id + "/deck.json": deck,
+	}
+}
+
+// mergeTestPacks loads the base deck and the packs from memory and merges
+// the enabled ones
+func mergeTestPacks(t *testing.T, packs []map[string]string, enabled []string) (*Deck, []string) {
+	fsys := fstest.MapFS{"deck.json": {Data: []byte(packTestDeck)}}
+	for _, files := range packs {
+		for name, data := range files {
+			fsys[name] = &fstest.MapFile{Data: []byte(data)}
+		}
+	}
+	saved := assets
+	assets = fsys
+	t.Cleanup(func() { assets = saved })
+
+	deck, _, _, err := readDeck(fsys, "deck.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, report := mergePacks(deck, ".", discoverPacks("."), enabled)
+	return deck, report
+}
+
+// deckCard returns the top-level card with the ID, or nil
+func deckCard(deck *Deck, id string) *Card {
+	for _, card := range deck.definedCards() {
+		if card.ID == id {
+			return card
+		}
+	}
+	return nil
+}
+
+func TestMergePacks(t *testing.T) {
+	tests := []struct {
+		name       string
+		packs      []map[string]string
+		enabled    []string
+		check      func(t *testing.T, deck *Deck)
+		wantReport []string
+		skipped    string // Pack whose errors make up the whole report
+	}{
+		{
+			name:    "override replaces the card",
+			packs:   []map[string]string{packFiles("o", `{"override": [{"id": "B", "text": "new b"}]}`)},
+			enabled: []string{"o"},
+			check: func(t *testing.T, deck *Deck) {
+				if b := deckCard(deck, "B"); b == nil || b.Text != "new b" || b.MaxUses != 1 {
+					t.Errorf("B = %+v, want the override with maxUses 1", b)
+				}
+			},
+		},
+		{
+			name:    "override a followup card",
+			packs:   []map[string]string{packFiles("o", `{"override": [{"id": "G", "text": "new g"}]}`)},
+			enabled: []string{"o"},
+			check: func(t *testing.T, deck *Deck) {
+				if g := deckCard(deck, "G"); g == nil || g.Text != "new g" {
+					t.Errorf("G = %+v, want the override", g)
+				}
+			},
+		},
+		{
+			name:    "patch with a null field",
+			packs:   []map[string]string{packFiles("p", `{"patch": [{"id": "A", "text": "patched", "image": null}]}`)},
+			enabled: []string{"p"},
+			check: func(t *testing.T, deck *Deck) {
+				a := deckCard(deck, "A")
+				if a.Text != "patched" || a.Image != "" || a.YesText != "keep" {
+					t.Errorf("A = %+v, want new text, no image and the old yesText", a)
+				}
+				if len(a.YesFollowups) != 1 || a.YesFollowups[0].ID != "F" {
+					t.Errorf("A followups = %+v, want the ref to F", a.YesFollowups)
+				}
+			},
+		},
+		{
+			name:    "patch followups get defaults",
+			packs:   []map[string]string{packFiles("p", `{"patch": [{"id": "C", "noFollowups": [{"id": "N", "text": "n"}, {"id": "M", "text": "m", "maxUses": 3, "delay": 0}]}]}`)},
+			enabled: []string{"p"},
+			check: func(t *testing.T, deck *Deck) {
+				followups := deckCard(deck, "C").NoFollowups
+				if len(followups) != 2 {
+					t.Fatalf("C has %d followups, want 2", len(followups))
+				}
+				if n := followups[0]; n.ID != "p:N" || n.MaxUses != 1 || n.Delay != 1 {
+					t.Errorf("first followup = %+v, want p:N with maxUses 1 and delay 1", n)
+				}
+				if m := followups[1]; m.ID != "p:M" || m.MaxUses != 3 || m.Delay != 0 {
+					t.Errorf("second followup = %+v, want p:M with maxUses 3 and delay 0", m)
+				}
+			},
+		},
+		{
+			name:    "patch a followup card",
+			packs:   []map[string]string{packFiles("p", `{"patch": [{"id": "F", "text": "new f"}]}`)},
+			enabled: []string{"p"},
+			check: func(t *testing.T, deck *Deck) {
+				if f := deckCard(deck, "F"); f == nil || f.Text != "new f" {
+					t.Errorf("F = %+v, want the patched text", f)
+				}
+			},
+		},
+		{
+			name:    "remove",
+			packs:   []map[string]string{packFiles("r", `{"remove": ["C", "G"]}`)},
+			enabled: []string{"r"},
+			check: func(t *testing.T, deck *Deck) {
+				if deckCard(deck, "C") != nil || deckCard(deck, "G") != nil {
+					t.Error("C and G still in the deck")
+				}
+				if deckCard(deck, "B") == nil || deckCard(deck, "F") == nil {
+					t.Error("B or F removed")
+				}
+			},
+		},
+		{
+			name: "dependencies load first",
+			packs: []map[string]string{
+				packFiles("a", `{"patch": [{"id": "b:X", "text": "patched by a"}], "cards": [{"id": "Y", "text": "y", "yesFollowups": [{"ref": "b:X"}]}]}`, "b"),
+				packFiles("b", `{"cards": [{"id": "X", "text": "x"}]}`),
+			},
+			enabled: []string{"a", "b"},
+			check: func(t *testing.T, deck *Deck) {
+				if x := deckCard(deck, "b:X"); x == nil || x.Text != "patched by a" {
+					t.Errorf("b:X = %+v, want the patch of a", x)
+				}
+				if deckCard(deck, "a:Y") == nil {
+					t.Error("a:Y not in the deck")
+				}
+			},
+		},
+		{
+			name: "disabled dependency",
+			packs: []map[string]string{
+				packFiles("a", `{"cards": [{"id": "Y", "text": "y"}]}`, "b"),
+				packFiles("b", `{"cards": [{"id": "X", "text": "x"}]}`),
+			},
+			enabled:    []string{"a"},
+			wantReport: []string{"a: skipped, dependency b is disabled"},
+			check: func(t *testing.T, deck *Deck) {
+				if deckCard(deck, "a:Y") != nil {
+					t.Error("a:Y merged without its dependency")
+				}
+			},
+		},
+		{
+			name: "missing targets",
+			packs: []map[string]string{packFiles("m", `{
+				"override": [{"id": "Z", "text": "z"}],
+				"patch": [{"id": "Z", "text": "z"}],
+				"remove": ["Z"],
+				"cards": [{"id": "Z", "text": "own z"}]
+			}`)},
+			enabled: []string{"m"},
+			wantReport: []string{
+				"m: override target Z not found",
+				"m: patch target Z not found",
+				"m: remove target Z not found",
+			},
+			check: func(t *testing.T, deck *Deck) {
+				if z := deckCard(deck, "m:Z"); z == nil || z.Text != "own z" {
+					t.Errorf("m:Z = %+v, want the pack's own card", z)
+				}
+			},
+		},
+		{
+			name: "broken pack is skipped",
+			packs: []map[string]string{
+				packFiles("bad", `{"remove": ["A"], "cards": [{"id": "X", "text": "x", "noFollowups": [{"ref": "NOPE"}]}]}`),
+				packFiles("good", `{"patch": [{"id": "B", "text": "patched by good"}]}`),
+			},
+			enabled: []string{"bad", "good"},
+			skipped: "bad",
+			check: func(t *testing.T, deck *Deck) {
+				if deckCard(deck, "A") == nil || deckCard(deck, "bad:X") != nil {
+					t.Error("changes of the broken pack kept")
+				}
+				if b := deckCard(deck, "B"); b.Text != "patched by good" {
+					t.Errorf("B = %+v, want the patch of good", b)
+				}
+			},
+		},
+		{
+			name: "two packs change one card",
+			packs: []map[string]string{
+				packFiles("one", `{"patch": [{"id": "B", "text": "one"}]}`),
+				packFiles("two", `{"override": [{"id": "B", "text": "two"}]}`),
+			},
+			enabled:    []string{"one", "two"},
+			wantReport: []string{"B: changed by one (patch), two (override); two (override) applies last"},
+			check: func(t *testing.T, deck *Deck) {
+				if b := deckCard(deck, "B"); b.Text != "two" {
+					t.Errorf("B = %+v, want the override of two", b)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deck, report := mergeTestPacks(t, tt.packs, tt.enabled)
+			tt.check(t, deck)
+
+			if tt.skipped != "" {
+				for _, line := range report {
+					if !strings.HasPrefix(line, tt.skipped+": skipped, ") {
+						t.Errorf("report line %q, want %s skipped", line, tt.skipped)
+					}
+				}
+				if len(report) == 0 {
+					t.Errorf("empty report, want %s skipped", tt.skipped)
+				}
+				return
+			}
+			if !slices.Equal(report, tt.wantReport) {
+				t.Errorf("report\n%s\nwant\n%s", strings.Join(report, "\n"), strings.Join(tt.wantReport, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	profilesFile     = "profiles.json"
+	profilesDir      = "profiles"
+	defaultProfileID = "default"
+	maxProfileName   = 20
+
+	// Profile rows, in unscaled units
+	profileRowHeight  = 44.0
+	profileRowGap     = 8.0
+	profileScrollStep = 40.0
+)
+
+// Files that belong to a profile. Before profiles existed they were kept
+// directly in the user data directory.
+var profileFiles = []string{settingsFile, statsFile, endingsFile, achievementsFile, dailyFile, replayDir}
+
+// ID of the profile whose files are read and written
+var activeProfileID = defaultProfileID
+
+// Profile is one player on this machine
+type Profile struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ProfileList holds every profile and which one is playing
+type ProfileList struct {
+	Active   string     `json:"active"`
+	Profiles []*Profile `json:"profiles"`
+}
+
+// profileFile returns the path of a file in the active profile's directory,
+// relative to the user data directory
+func profileFile(name string) string {
+	return filepath.Join(profilesDir, activeProfileID, name)
+}
+
+// loadProfiles reads the profile list, creating the default profile on the
+// first start, and activates the last used profile
+func loadProfiles() *ProfileList {
+	list := &ProfileList{}
+	if err := loadUserJSON(profilesFile, list); err != nil {
+		log.Printf("Failed to load profiles: %v", err)
+	}
+
+	if len(list.Profiles) == 0 {
+		list.Profiles = []*Profile{{ID: defaultProfileID, Name: tr("profiles.defaultName"), CreatedAt: time.Now()}}
+		list.Active = defaultProfileID
+		migrateLegacyFiles()
+		list.save()
+	}
+
+	if list.Find(list.Active) == nil {
+		list.Active = list.Profiles[0].ID
+	}
+	activeProfileID = list.Active
+	return list
+}
+
+// migrateLegacyFiles moves data saved before profiles existed into the
+// default profile
+func migrateLegacyFiles() {
+	for _, name := range profileFiles {
+		from, err := userDataPath(name)
+		if err != nil {
+			log.Printf("Failed to migrate %s: %v", name, err)
+			continue
+		}
+		to, err := userDataPath(filepath.Join(profilesDir, defaultProfileID, name))
+		if err != nil {
+			log.Printf("Failed to migrate %s: %v", name, err)
+			continue
+		}
+		if err := os.Rename(from, to); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Failed to migrate %s: %v", name, err)
+		}
+	}
+}
+
+func (l *ProfileList) save() {
+	if err := saveUserJSON(profilesFile, l); err != nil {
+		log.Printf("Failed to save profiles: %v", err)
+	}
+}
+
+// Find returns the profile with the given ID, or nil
+func (l *ProfileList) Find(id string) *Profile {
+	for _, profile := range l.Profiles {
+		if profile.ID == id {
+			return profile
+		}
+	}
+	return nil
+}
+
+// Add creates a new profile and returns it
+func (l *ProfileList) Add(name string, now time.Time) *Profile {
+	id := ""
+	for i := len(l.Profiles) + 1; id == "" || l.Find(id) != nil; i++ {
+		id = fmt.Sprintf("profile-%d", i)
+	}
+
+	profile := &Profile{ID: id, Name: name, CreatedAt: now}
+	l.Profiles = append(l.Profiles, profile)
+	l.save()
+	return profile
+}
+
+// loadProfileData reads everything that belongs to the active profile
+func (g *Game) loadProfileData() {
+	g.settings = loadSettings()
+	g.stats = loadStats()
+	g.endings = loadEndings()
+	g.unlocks = loadAchievements()
+	g.daily = loadDailyHistory()
+}
+
+// switchProfile makes another profile active and starts a fresh run for it
+func (g *Game) switchProfile(id string) {
+	if g.profiles.Find(id) == nil || id == activeProfileID {
+		return
+	}
+
+	g.profiles.Active = id
+	g.profiles.save()
+	activeProfileID = id
+
+	g.loadProfileData()
+	g.applySettings()
+	g.toasts = nil
+
+	// Profiles enable their own packs
+	g.loadCards(g.deckFile)
+	g.restartGame()
+}
+
+// profilesGeometry lays out the profiles panel, the scrolling list with one
+// row per profile, before scrolling, and the new profile button below it
+func (g *Game) profilesGeometry() (panel, list Rect, rows []Rect, newButton Rect) {
+	l := g.layout
+	s := l.Scale
+	rowHeight := profileRowHeight * s
+	spacing := profileRowGap * s
+
+	width := min(400*s, l.Width-20*s)
+	height := min(80*s+float64(len(g.profiles.Profiles))*(rowHeight+spacing)+80*s, l.Height-20*s)
+	panel = Rect{X: (l.Width - width) / 2, Y: (l.Height - height) / 2, Width: width, Height: height}
+	list = Rect{X: panel.X + 20*s, Y: panel.Y + 70*s, Width: width - 40*s, Height: height - 160*s}
+
+	y := list.Y
+	for range g.profiles.Profiles {
+		rows = append(rows, Rect{X: list.X, Y: y, Width: list.Width, Height: rowHeight})
+		y += rowHeight + spacing
+	}
+	newButton = Rect{X: panel.CenterX() - 100*s, Y: list.Y + list.Height + 14*s, Width: 200 * s, Height: 44 * s}
+	return panel, list, rows, newButton
+}
+
+// maxProfilesScroll is how far the list can scroll before its end is visible
+func (g *Game) maxProfilesScroll(list Rect) float64 {
+	s := g.layout.Scale
+	content := float64(len(g.profiles.Profiles)) * (profileRowHeight + profileRowGap) * s
+	return max(0, content-list.Height)
+}
+
+// updateProfiles handles switching profiles and typing a new profile name
+func (g *Game) updateProfiles() {
+	// Typing the name of a new profile
+	if g.newProfileName != nil {
+		g.newProfileName = ebiten.AppendInputChars(g.newProfileName)
+		if len(g.newProfileName) > maxProfileName {
+			g.newProfileName = g.newProfileName[:maxProfileName]
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(g.newProfileName) > 0 {
+			g.newProfileName = g.newProfileName[:len(g.newProfileName)-1]
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			g.newProfileName = nil
+			return
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyKPEnter) {
+			name := strings.TrimSpace(string(g.newProfileName))
+			g.newProfileName = nil
+			if name != "" {
+				g.switchProfile(g.profiles.Add(name, g.clock.Now()).ID)
+			}
+		}
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.returnToGame()
+		return
+	}
+
+	panel, list, rows, newButton := g.profilesGeometry()
+	s := g.layout.Scale
+
+	// Mouse wheel and keyboard scrolling
+	_, wheel := ebiten.Wheel()
+	g.profilesScroll -= wheel * profileScrollStep * s
+	if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+		g.profilesScroll += profileScrollStep * s
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+		g.profilesScroll -= profileScrollStep * s
+	}
+	g.profilesScroll = max(0, min(g.profilesScroll, g.maxProfilesScroll(list)))
+
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+
+	mx, my := ebiten.CursorPosition()
+	x, y := float64(mx), float64(my)
+
+	for i, row := range rows {
+		if list.Contains(x, y) && row.Contains(x, y+g.profilesScroll) {
+			g.switchProfile(g.profiles.Profiles[i].ID)
+			g.returnToGame()
+			return
+		}
+	}
+
+	if newButton.Contains(x, y) {
+		g.newProfileName = []rune{}
+		return
+	}
+
+	// Click outside the panel closes the screen
+	if !panel.Contains(x, y) {
+		g.returnToGame()
+	}
+}
+
+func (g *Game) drawProfilesScreen(screen *ebiten.Image) {
+	l := g.layout
+	s := l.Scale
+	panel, list, rows, newButton := g.profilesGeometry()
+
+	// Draw overlay and panel
+	drawOverlay(screen, l)
+	vector.DrawFilledRect(screen, float32(panel.X), float32(panel.Y), float32(panel.Width), float32(panel.Height), colorPanel, true)
+
+	// Title
+	title := tr("profiles.title")
+	w, _ := getBoundsSize(boldFont, title)
+	drawTextWithOptions(screen, title, boldFont, int(panel.CenterX())-w/2, int(panel.Y+45*s), colorTextLight)
+
+	// Profiles, the active one highlighted, clipped to the list area
+	mx, my := ebiten.CursorPosition()
+	clip := screen.SubImage(image.Rect(int(list.X), int(list.Y), int(list.X+list.Width), int(list.Y+list.Height))).(*ebiten.Image)
+	for i, row := range rows {
+		row.Y -= g.profilesScroll
+		if row.Y+row.Height < list.Y || row.Y > list.Y+list.Height {
+			continue
+		}
+
+		profile := g.profiles.Profiles[i]
+		clr := colorAboutBtn
+		if profile.ID == activeProfileID {
+			clr = colorRestartBtn
+		}
+		g.drawButton(clip, Button{
+			X: row.X, Y: row.Y, Width: row.Width, Height: row.Height,
+			Text: profile.Name, Color: clr, HoverColor: colorRestartHover, TextColor: colorTextLight,
+			IsHovered: list.Contains(float64(mx), float64(my)) && row.Contains(float64(mx), float64(my)),
+		})
+	}
+
+	// New profile button, or the name being typed
+	text := tr("profiles.new")
+	if g.newProfileName != nil {
+		text = string(g.newProfileName) + "_"
+	}
+	g.drawButton(screen, Button{
+		X: newButton.X, Y: newButton.Y, Width: newButton.Width, Height: newButton.Height,
+		Text: text, Color: colorAboutHover, HoverColor: colorRestartHover, TextColor: colorTextLight,
+		IsHovered: g.newProfileName == nil && newButton.Contains(float64(mx), float64(my)),
+	})
+	if g.newProfileName != nil {
+		hint := tr("profiles.nameHint")
+		w, _ := getBoundsSize(smallFont, hint)
+		drawTextWithOptions(screen, hint, smallFont, int(panel.CenterX())-w/2, int(newButton.Y+newButton.Height+18*s), colorTextLight)
+	}
+}
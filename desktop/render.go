@@ -23,14 +23,35 @@ type Button struct {
 	IsHovered           bool
 }
 
+// setRect moves and resizes the button
+func (b *Button) setRect(r Rect) {
+	b.X, b.Y, b.Width, b.Height = r.X, r.Y, r.Width, r.Height
+}
+
+// Contains reports whether the given cursor position is over the button
+func (b *Button) Contains(x, y int) bool {
+	return Rect{X: b.X, Y: b.Y, Width: b.Width, Height: b.Height}.Contains(float64(x), float64(y))
+}
+
 func (g *Game) drawGameScreen(screen *ebiten.Image) {
-	// Draw day counter
-	dayText := fmt.Sprintf("Gün %d", g.resources.Day)
+	l := g.layout
+
+	// Draw day counter (centered over the card)
+	dayText := fmt.Sprintf(tr("day"), g.resources.Day)
+	if g.mode == modeDaily {
+		dayText += " · " + tr("daily.title")
+	} else if g.mode == modeCasual {
+		dayText += " · " + tr("casual.title")
+	}
+	// A run changed from the console or by a deck reload is left out of the records
+	if g.debugRun && g.player == nil {
+		dayText += " · " + tr("run.unrecorded")
+	}
 	w, _ := getBoundsSize(boldFont, dayText)
 	drawTextWithOptions(screen, dayText, boldFont,
-		(screenWidth-w)/2,
-		50,
-		colorTextPrimary)
+		int(l.Card.CenterX())-w/2,
+		int(l.DayTextY),
+		colorTextOnBackground)
 
 	// Draw stats
 	g.drawStats(screen)
@@ -43,51 +64,84 @@ func (g *Game) drawGameScreen(screen *ebiten.Image) {
 		g.drawCard(screen)
 	}
 
-	// Draw about button
+	// Draw about and sound buttons
 	g.drawButton(screen, g.aboutButton)
+	g.drawButton(screen, g.soundButton)
+
+	// Undo button, only in casual mode
+	if g.mode == modeCasual {
+		b := g.undoButton
+		b.Text = "← " + g.undosLeft()
+		if !g.canUndo() {
+			b.Color = withOpacity(b.Color, 0.5)
+			b.HoverColor = b.Color
+		}
+		g.drawButton(screen, b)
+	}
+	g.drawButton(screen, g.settingsButton)
+	if g.settings.Audio.Muted {
+		// Strike through the note while muted
+		b := g.soundButton
+		inset := b.Width * 0.25
+		vector.StrokeLine(screen, float32(b.X+inset), float32(b.Y+b.Height-inset), float32(b.X+b.Width-inset), float32(b.Y+inset),
+			float32(2*g.layout.Scale), b.TextColor, true)
+	}
 
 	// Draw copyright
-	copyrightText := "© 2025 Office Politics."
+	copyrightText := tr("copyright")
 	w, _ = getBoundsSize(smallFont, copyrightText)
 	drawTextWithOptions(screen, copyrightText, smallFont,
-		(screenWidth-w)/2,
-		screenHeight-10,
+		int(l.Width)/2-w/2,
+		int(l.CopyrightY),
 		colorSwipeHint)
 }
 
 func (g *Game) drawStats(screen *ebiten.Image) {
 	// Stats container
-	statContainerWidth := 280.0
-	statContainerHeight := 65.0
-	statContainerX := (screenWidth - statContainerWidth) / 2
-	statContainerY := 70.0
+	container := g.layout.Stats
 
 	// Draw container
-	vector.DrawFilledRect(screen, float32(statContainerX), float32(statContainerY), float32(statContainerWidth), float32(statContainerHeight), colorCard, true)
+	vector.DrawFilledRect(screen, float32(container.X), float32(container.Y), float32(container.Width), float32(container.Height), colorCard, true)
+
+	// Icons run along the long side of the container
+	length, depth := container.Width, container.Height
+	if g.layout.StatsVertical {
+		length, depth = container.Height, container.Width
+	}
 
 	// Draw stat icons
-	iconSize := 45.0
-	spacing := (statContainerWidth - 4*iconSize) / 5
-	iconY := statContainerY + (statContainerHeight-iconSize)/2
+	iconSize := 45.0 * g.layout.Scale
+	spacing := (length - 4*iconSize) / 5
+	offset := (depth - iconSize) / 2
+
+	// iconPos returns the top-left corner of the i-th icon
+	iconPos := func(i int) (float64, float64) {
+		along := spacing + float64(i)*(iconSize+spacing)
+		if g.layout.StatsVertical {
+			return container.X + offset, container.Y + along
+		}
+		return container.X + along, container.Y + offset
+	}
 
-	motivationX := statContainerX + spacing
-	g.drawStatIcon(screen, motivationX, iconY, iconSize,
-		g.resources.Motivation, colorMotivation, "M", "Motivasyon")
+	// Motivation stat (heart)
+	x, y := iconPos(0)
+	g.drawStatIcon(screen, x, y, iconSize,
+		g.resources.Motivation, colorMotivation, "M", tr("stat.motivation"))
 
 	// Performance stat (chart)
-	performanceX := motivationX + iconSize + spacing
-	g.drawStatIcon(screen, performanceX, iconY, iconSize,
-		g.resources.Performance, colorPerformance, "P", "Performans")
+	x, y = iconPos(1)
+	g.drawStatIcon(screen, x, y, iconSize,
+		g.resources.Performance, colorPerformance, "P", tr("stat.performance"))
 
 	// Colleagues stat (people)
-	colleaguesX := performanceX + iconSize + spacing
-	g.drawStatIcon(screen, colleaguesX, iconY, iconSize,
-		g.resources.Colleagues, colorColleagues, "A", "İş Arkadaşları")
+	x, y = iconPos(2)
+	g.drawStatIcon(screen, x, y, iconSize,
+		g.resources.Colleagues, colorColleagues, "A", tr("stat.colleagues"))
 
 	// Boss stat (tie)
-	bossX := colleaguesX + iconSize + spacing
-	g.drawStatIcon(screen, bossX, iconY, iconSize,
-		g.resources.Boss, colorBoss, "P", "Patron")
+	x, y = iconPos(3)
+	g.drawStatIcon(screen, x, y, iconSize,
+		g.resources.Boss, colorBoss, "P", tr("stat.boss"))
 }
 
 func (g *Game) drawStatIcon(screen *ebiten.Image, x, y, size float64,
@@ -101,7 +155,8 @@ func (g *Game) drawStatIcon(screen *ebiten.Image, x, y, size float64,
 	vector.DrawFilledCircle(screen, float32(centerX), float32(centerY), float32(radius), colorCardBorder, true)
 
 	// Draw white background (slightly smaller for border effect)
-	vector.DrawFilledCircle(screen, float32(centerX), float32(centerY), float32(radius-2), colorCard, true)
+	border := 2 * g.layout.Scale
+	vector.DrawFilledCircle(screen, float32(centerX), float32(centerY), float32(radius-border), colorCard, true)
 
 	// Draw fill based on value percentage
 	if value > 0 {
@@ -109,7 +164,7 @@ func (g *Game) drawStatIcon(screen *ebiten.Image, x, y, size float64,
 		fillImg := ebiten.NewImage(int(size), int(size))
 
 		// Fill the temporary image with our color
-		vector.DrawFilledCircle(fillImg, float32(size/2), float32(size/2), float32(radius-2), fillColor, true)
+		vector.DrawFilledCircle(fillImg, float32(size/2), float32(size/2), float32(radius-border), fillColor, true)
 
 		// Calculate how much of the circle to show based on value
 		fillHeight := size * float64(value) / 100.0
@@ -145,77 +200,80 @@ func (g *Game) drawStatIcon(screen *ebiten.Image, x, y, size float64,
 	drawTextWithOptions(screen, symbol, regularFont, textX, textY, colorTextPrimary)
 }
 
+// Card stack constants
+const (
+	maxStackDepth     = 3
+	stackOffsetStep   = 12.0
+	stackScaleStep    = 0.05
+	stackOpacityStep  = 0.2
+	stackFadeOutDepth = maxStackDepth + 1
+)
+
+// stackItemStyle returns the offset, scale and opacity of a card at the
+// given depth behind the active card. Depth is fractional while the stack
+// slides forward so cards move smoothly between slots.
+func stackItemStyle(depth float64) (offsetY, scale, opacity float64) {
+	offsetY = stackOffsetStep * depth
+	scale = 1 - stackScaleStep*depth
+	opacity = 1 - stackOpacityStep*depth
+
+	// Fade in the card entering at the back of the stack
+	if depth > maxStackDepth {
+		opacity *= stackFadeOutDepth - depth
+	}
+	return
+}
+
+// stackDepth returns how many cards to show behind the active card,
+// reflecting the cards left in the current cycle
+func (g *Game) stackDepth() int {
+	return min(maxStackDepth, len(g.availableCards))
+}
+
 func (g *Game) drawCardStack(screen *ebiten.Image) {
-	return // Currently disabled
+	depth := g.stackDepth()
+	if depth == 0 {
+		return
+	}
 
 	// Card dimensions and position
-	cardWidth := 400.0
-	cardHeight := 500.0
-	cardX := (screenWidth - cardWidth) / 2
-	cardY := (screenHeight-cardHeight)/2 + 50 // Offset down a bit from center
+	s := g.layout.Scale
+	card := g.layout.Card
+
+	// Render the back of a card once per size and theme
+	if g.stackCardImg == nil || g.stackTheme != currentTheme ||
+		g.stackCardImg.Bounds().Dx() != int(card.Width) || g.stackCardImg.Bounds().Dy() != int(card.Height) {
+		g.stackCardImg = ebiten.NewImage(int(card.Width), int(card.Height))
+		drawCardShape(g.stackCardImg, 0, 0, float32(card.Width), float32(card.Height), s, colorCard, colorCardBorder)
+		g.stackTheme = currentTheme
+	}
 
-	// Draw stack items (back to front)
-	for i := len(g.stackItems) - 1; i >= 0; i-- {
-		item := g.stackItems[i]
-
-		// Apply stack item offsets
-		itemX := cardX + item.X
-		itemY := cardY + item.Y
-
-		// Draw stack card with semi-transparency
-		alpha := uint8(item.Opacity * 255)
-		bgColor := color.RGBA{255, 255, 255, alpha}
-
-		// Draw stack card background
-		vector.DrawFilledRect(screen,
-			float32(itemX),
-			float32(itemY),
-			float32(cardWidth),
-			float32(cardHeight),
-			bgColor,
-			true)
-
-		// Draw stack card border
-		borderColor := color.RGBA{55, 65, 81, alpha}
-
-		// Create a path for stroking the border
-		path := vector.Path{}
-
-		// Define the rectangle for the border
-		path.MoveTo(float32(itemX), float32(itemY))
-		path.LineTo(float32(itemX+cardWidth), float32(itemY))
-		path.LineTo(float32(itemX+cardWidth), float32(itemY+cardHeight))
-		path.LineTo(float32(itemX), float32(itemY+cardHeight))
-		path.LineTo(float32(itemX), float32(itemY))
-
-		// Create stroke options
-		strokeOptions := &vector.StrokeOptions{
-			Width:    1,
-			LineJoin: vector.LineJoinMiter,
-			LineCap:  vector.LineCapButt,
-		}
+	// While the active card flies away, a new card enters at the back
+	count := depth
+	if g.stackProgress > 0 && len(g.availableCards) > depth {
+		count++
+	}
 
-		// Stroke the path to draw the border
-		vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, strokeOptions)
-		for i := range vs {
-			vs[i].SrcX = 1
-			vs[i].SrcY = 1
-			vs[i].ColorR = float32(borderColor.R) / 255
-			vs[i].ColorG = float32(borderColor.G) / 255
-			vs[i].ColorB = float32(borderColor.B) / 255
-			vs[i].ColorA = float32(borderColor.A) / 255
-		}
+	// Draw stack items (back to front)
+	for i := count; i >= 1; i-- {
+		offsetY, scale, opacity := stackItemStyle(float64(i) - g.stackProgress)
 
-		screen.DrawTriangles(vs, is, emptySubImage, &ebiten.DrawTrianglesOptions{})
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-card.Width/2, -card.Height/2)
+		op.GeoM.Scale(scale, scale)
+		op.GeoM.Translate(card.CenterX(), card.CenterY()+offsetY*s)
+		op.ColorScale.ScaleAlpha(float32(opacity))
+		screen.DrawImage(g.stackCardImg, op)
 	}
 }
 
 func (g *Game) drawCard(screen *ebiten.Image) {
 	// Card dimensions and position
-	cardWidth := 400.0
-	cardHeight := 500.0
-	basePosX := (screenWidth - cardWidth) / 2
-	basePosY := (screenHeight-cardHeight)/2 + 50 // Offset down a bit from center
+	s := g.layout.Scale
+	cardWidth := g.layout.Card.Width
+	cardHeight := g.layout.Card.Height
+	basePosX := g.layout.Card.X
+	basePosY := g.layout.Card.Y
 
 	// Apply card animation transformations
 	cardPosX := basePosX + g.cardX
@@ -230,25 +288,15 @@ func (g *Game) drawCard(screen *ebiten.Image) {
 		bgColor = colorInfoCard
 	} else {
 		// Apply gradient color based on drag position
-		if g.dragging && math.Abs(g.currentX) > 30 {
+		if g.dragging && math.Abs(g.currentX) > 30*s {
 			if g.currentX > 0 {
-				// Swiping right - green tint
-				greenIntensity := math.Min(math.Abs(g.currentX)/400, 0.3)
-				bgColor = color.RGBA{
-					uint8(255 - greenIntensity*100), // Reduce red to make it more green
-					uint8(255),                      // Keep green at maximum
-					uint8(255 - greenIntensity*150), // Reduce blue to make it more green
-					255,
-				}
+				// Swiping right - tint towards the yes color
+				yesIntensity := math.Min(math.Abs(g.currentX)/cardWidth, 0.3)
+				bgColor = mixColor(colorCard, colorYesOption, yesIntensity)
 			} else {
-				// Swiping left - red tint
-				redIntensity := math.Min(math.Abs(g.currentX)/400, 0.3)
-				bgColor = color.RGBA{
-					uint8(255),
-					uint8(255 - redIntensity*150),
-					uint8(255 - redIntensity*150),
-					255,
-				}
+				// Swiping left - tint towards the no color
+				noIntensity := math.Min(math.Abs(g.currentX)/cardWidth, 0.3)
+				bgColor = mixColor(colorCard, colorNoOption, noIntensity)
 			}
 		} else {
 			bgColor = colorCard
@@ -258,10 +306,7 @@ func (g *Game) drawCard(screen *ebiten.Image) {
 	// Clear the card image with transparency
 	cardImg.Clear()
 
-	// Draw rectangle for the card background (sharp corners)
-	vector.DrawFilledRect(cardImg, 0, 0, float32(cardWidth), float32(cardHeight), bgColor, true)
-
-	// Draw border on the card image (sharp corners)
+	// Pick border color
 	var borderColor color.RGBA
 	if g.currentCard.IsInfoOnly {
 		borderColor = colorInfoBorder
@@ -269,35 +314,36 @@ func (g *Game) drawCard(screen *ebiten.Image) {
 		borderColor = colorCardBorder
 	}
 
-	// Draw border lines manually for sharp corners
-	borderWidth := float32(1)                                                                                                                               // Adjust border thickness if needed
-	vector.StrokeLine(cardImg, 0, 0, float32(cardWidth), 0, borderWidth, borderColor, true)                                                                 // Top
-	vector.StrokeLine(cardImg, float32(cardWidth)-borderWidth/2, 0, float32(cardWidth)-borderWidth/2, float32(cardHeight), borderWidth, borderColor, true)  // Right (adjust for thickness)
-	vector.StrokeLine(cardImg, float32(cardWidth), float32(cardHeight)-borderWidth/2, 0, float32(cardHeight)-borderWidth/2, borderWidth, borderColor, true) // Bottom (adjust for thickness)
-	vector.StrokeLine(cardImg, borderWidth/2, float32(cardHeight), borderWidth/2, 0, borderWidth, borderColor, true)                                        // Left (adjust for thickness)
+	// Draw the card background and border using the theme's shape
+	drawCardShape(cardImg, 0, 0, float32(cardWidth), float32(cardHeight), s, bgColor, borderColor)
 
 	// Draw card text
-	textMargin := 20
+	textMargin := int(20 * s)
 	textWidth := int(cardWidth) - 2*textMargin
 	textX := textMargin
 	textY := int(cardHeight) / 3
 
+	// Draw illustration and speaker, pushing the text below them
+	if headerBottom := g.drawCardHeader(cardImg, s); headerBottom > 0 {
+		textY = int(headerBottom) + lineHeightFor(regularFont)
+	}
+
 	// Draw text with proper wrapping
 	drawWrappedText(cardImg, g.currentCard.Text, regularFont, textX, textY, textWidth, colorTextPrimary)
 
 	// Draw decision options if not info card
 	if !g.currentCard.IsInfoOnly {
 		// Define drag threshold for showing options
-		dragThreshold := 30.0
+		dragThreshold := 30 * s
 
 		// Yes option (right side)
 		yesText := g.currentCard.YesText
 		if yesText == "" {
-			yesText = "Evet"
+			yesText = tr("yes")
 		}
 		w, _ := getBoundsSize(boldFont, yesText)
 		yesX := int(cardWidth) - textMargin - w
-		yesY := int(cardHeight) - 40
+		yesY := int(cardHeight - 40*s)
 
 		// Only show "Yes" option when dragging right past threshold
 		if g.dragging && g.currentX > dragThreshold {
@@ -307,10 +353,10 @@ func (g *Game) drawCard(screen *ebiten.Image) {
 		// No option (left side)
 		noText := g.currentCard.NoText
 		if noText == "" {
-			noText = "Hayır"
+			noText = tr("no")
 		}
 		noX := textMargin
-		noY := int(cardHeight) - 40
+		noY := int(cardHeight - 40*s)
 
 		// Only show "No" option when dragging left past threshold
 		if g.dragging && g.currentX < -dragThreshold {
@@ -319,18 +365,18 @@ func (g *Game) drawCard(screen *ebiten.Image) {
 
 		// If not dragging far enough in either direction, show swipe hint
 		if !g.dragging || math.Abs(g.currentX) <= dragThreshold {
-			swipeText := "Kaydırmak için sürükle"
+			swipeText := tr("swipeHint")
 			w, _ := getBoundsSize(smallFont, swipeText)
 			swipeX := (int(cardWidth) - w) / 2
-			swipeY := int(cardHeight) - 30
+			swipeY := int(cardHeight - 30*s)
 			drawTextWithOptions(cardImg, swipeText, smallFont, swipeX, swipeY, colorSwipeHint)
 		}
 	} else {
 		// If info card, show swipe indicator
-		swipeText := "Kaydırmak için sürükle"
+		swipeText := tr("swipeHint")
 		w, _ := getBoundsSize(smallFont, swipeText)
 		swipeX := (int(cardWidth) - w) / 2
-		swipeY := int(cardHeight) - 30
+		swipeY := int(cardHeight - 30*s)
 		drawTextWithOptions(cardImg, swipeText, smallFont, swipeX, swipeY, colorSwipeHint)
 	}
 
@@ -349,6 +395,17 @@ func (g *Game) drawCard(screen *ebiten.Image) {
 	// Apply position
 	op.GeoM.Translate(cardPosX, cardPosY)
 
+	// Draw the theme's drop shadow with the card's silhouette
+	shadow := currentTheme.Shadow
+	if shadow.Color.A > 0 {
+		shadowOp := &ebiten.DrawImageOptions{}
+		shadowOp.GeoM = op.GeoM
+		shadowOp.GeoM.Translate(shadow.OffsetX*s, shadow.OffsetY*s)
+		shadowOp.ColorScale.ScaleWithColor(color.RGBA(shadow.Color))
+		shadowOp.ColorScale.ScaleAlpha(float32(g.cardOpacity))
+		screen.DrawImage(cardImg, shadowOp)
+	}
+
 	// Apply opacity
 	op.ColorScale.Scale(1, 1, 1, float32(g.cardOpacity))
 
@@ -356,8 +413,113 @@ func (g *Game) drawCard(screen *ebiten.Image) {
 	screen.DrawImage(cardImg, op)
 }
 
-// drawRoundedRect draws a rounded rectangle (kept for potential future use, but not used by drawCard anymore)
-func drawRoundedRect(dst *ebiten.Image, x, y, width, height, radius float32, clr color.RGBA, fill bool) {
+// drawCardHeader draws the card's illustration and the speaker's portrait
+// and nameplate at the top of the card image. It returns the y coordinate
+// below the header, or 0 if the card has neither.
+func (g *Game) drawCardHeader(cardImg *ebiten.Image, s float64) float64 {
+	cardWidth := float64(cardImg.Bounds().Dx())
+	cardHeight := float64(cardImg.Bounds().Dy())
+	margin := 20 * s
+	y := 0.0
+
+	// Illustration, scaled to fit the top of the card
+	if illustration := g.images.get(g.currentCard.Image); illustration != nil {
+		area := Rect{X: margin, Y: margin, Width: cardWidth - 2*margin, Height: cardHeight * 0.35}
+		drawImageFit(cardImg, illustration, area)
+		y = area.Y + area.Height
+	}
+
+	// Speaker portrait and nameplate
+	character := g.characters[g.currentCard.Speaker]
+	if character == nil {
+		return y
+	}
+
+	portraitSize := 56 * s
+	portraitX := margin
+	portraitY := margin
+	if y > 0 {
+		portraitY = y + margin/2
+	}
+
+	nameColor := color.RGBA(character.Color)
+	if portrait := g.images.get(character.Portrait); portrait != nil {
+		drawImageFit(cardImg, portrait, Rect{X: portraitX, Y: portraitY, Width: portraitSize, Height: portraitSize})
+	} else {
+		// No portrait, show the character's initial in their color
+		radius := portraitSize / 2
+		vector.DrawFilledCircle(cardImg, float32(portraitX+radius), float32(portraitY+radius), float32(radius), nameColor, true)
+		initial := string([]rune(character.Name)[:1])
+		w, h := getBoundsSize(boldFont, initial)
+		drawTextWithOptions(cardImg, initial, boldFont,
+			int(portraitX+radius)-w/2, int(portraitY+radius)+h/2, colorTextLight)
+	}
+
+	// Nameplate next to the portrait
+	w, h := getBoundsSize(boldFont, character.Name)
+	plateX := portraitX + portraitSize + 10*s
+	plateHeight := float64(h) + 16*s
+	plateY := portraitY + (portraitSize-plateHeight)/2
+	plateWidth := float64(w) + 24*s
+	drawRoundedRect(cardImg, float32(plateX), float32(plateY), float32(plateWidth), float32(plateHeight),
+		float32(plateHeight/2), nameColor, true, 0)
+	drawTextWithOptions(cardImg, character.Name, boldFont,
+		int(plateX+12*s), int(plateY+(plateHeight+float64(h))/2), colorTextLight)
+
+	return portraitY + portraitSize
+}
+
+// drawImageFit draws img scaled to fit inside area, keeping its aspect ratio
+func drawImageFit(dst, img *ebiten.Image, area Rect) {
+	w := float64(img.Bounds().Dx())
+	h := float64(img.Bounds().Dy())
+	scale := math.Min(area.Width/w, area.Height/h)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(area.X+(area.Width-w*scale)/2, area.Y+(area.Height-h*scale)/2)
+	op.Filter = ebiten.FilterLinear
+	dst.DrawImage(img, op)
+}
+
+// drawCardShape draws a card background and border using the active
+// theme's corner radius and border width
+func drawCardShape(dst *ebiten.Image, x, y, width, height float32, scale float64, fill, border color.RGBA) {
+	radius := float32(currentTheme.CardCornerRadius * scale)
+	borderWidth := float32(currentTheme.BorderWidth * scale)
+
+	if radius <= 0 {
+		// Sharp corners, border inset so it stays inside the image
+		vector.DrawFilledRect(dst, x, y, width, height, fill, true)
+		vector.StrokeRect(dst, x+borderWidth/2, y+borderWidth/2, width-borderWidth, height-borderWidth, borderWidth, border, true)
+		return
+	}
+
+	inset := borderWidth / 2
+	drawRoundedRect(dst, x, y, width, height, radius, fill, true, 0)
+	drawRoundedRect(dst, x+inset, y+inset, width-borderWidth, height-borderWidth, radius-inset, border, false, borderWidth)
+}
+
+// mixColor blends from a towards b by t in [0, 1]
+func mixColor(a, b color.RGBA, t float64) color.RGBA {
+	lerp := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t)
+	}
+	return color.RGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), lerp(a.A, b.A)}
+}
+
+// withOpacity scales a premultiplied color by the given opacity
+func withOpacity(c color.RGBA, opacity float64) color.RGBA {
+	return color.RGBA{
+		uint8(float64(c.R) * opacity),
+		uint8(float64(c.G) * opacity),
+		uint8(float64(c.B) * opacity),
+		uint8(float64(c.A) * opacity),
+	}
+}
+
+// drawRoundedRect draws a filled rounded rectangle, or its outline with the given stroke width
+func drawRoundedRect(dst *ebiten.Image, x, y, width, height, radius float32, clr color.RGBA, fill bool, strokeWidth float32) {
 	// Draw the fill if requested
 	if fill {
 		vector.DrawFilledRect(dst, x+radius, y, width-radius*2, height, clr, true)
@@ -370,21 +532,21 @@ func drawRoundedRect(dst *ebiten.Image, x, y, width, height, radius float32, clr
 		vector.DrawFilledCircle(dst, x+width-radius, y+height-radius, radius, clr, true)
 	} else {
 		// Draw the four sides of the border
-		vector.StrokeLine(dst, x+radius, y, x+width-radius, y, 1, clr, true)               // Top
-		vector.StrokeLine(dst, x+width, y+radius, x+width, y+height-radius, 1, clr, true)  // Right
-		vector.StrokeLine(dst, x+radius, y+height, x+width-radius, y+height, 1, clr, true) // Bottom
-		vector.StrokeLine(dst, x, y+radius, x, y+height-radius, 1, clr, true)              // Left
+		vector.StrokeLine(dst, x+radius, y, x+width-radius, y, strokeWidth, clr, true)               // Top
+		vector.StrokeLine(dst, x+width, y+radius, x+width, y+height-radius, strokeWidth, clr, true)  // Right
+		vector.StrokeLine(dst, x+radius, y+height, x+width-radius, y+height, strokeWidth, clr, true) // Bottom
+		vector.StrokeLine(dst, x, y+radius, x, y+height-radius, strokeWidth, clr, true)              // Left
 
 		// Draw the four corners
-		drawCornerArc(dst, x+radius, y+radius, radius, math.Pi, math.Pi*1.5, clr)        // Top-left
-		drawCornerArc(dst, x+width-radius, y+radius, radius, math.Pi*1.5, 0, clr)        // Top-right
-		drawCornerArc(dst, x+width-radius, y+height-radius, radius, 0, math.Pi*0.5, clr) // Bottom-right
-		drawCornerArc(dst, x+radius, y+height-radius, radius, math.Pi*0.5, math.Pi, clr) // Bottom-left
+		drawCornerArc(dst, x+radius, y+radius, radius, math.Pi, math.Pi*1.5, strokeWidth, clr)         // Top-left
+		drawCornerArc(dst, x+width-radius, y+radius, radius, math.Pi*1.5, math.Pi*2, strokeWidth, clr) // Top-right
+		drawCornerArc(dst, x+width-radius, y+height-radius, radius, 0, math.Pi*0.5, strokeWidth, clr)  // Bottom-right
+		drawCornerArc(dst, x+radius, y+height-radius, radius, math.Pi*0.5, math.Pi, strokeWidth, clr)  // Bottom-left
 	}
 }
 
-// drawCornerArc draws an arc for a corner of the rounded rectangle (kept for potential future use)
-func drawCornerArc(dst *ebiten.Image, x, y, radius float32, startAngle, endAngle float64, clr color.RGBA) {
+// drawCornerArc draws an arc for a corner of the rounded rectangle
+func drawCornerArc(dst *ebiten.Image, x, y, radius float32, startAngle, endAngle float64, strokeWidth float32, clr color.RGBA) {
 	// Number of segments to approximate the arc
 	segments := 16
 
@@ -401,7 +563,7 @@ func drawCornerArc(dst *ebiten.Image, x, y, radius float32, startAngle, endAngle
 		x2 := x + radius*float32(math.Cos(angle2))
 		y2 := y + radius*float32(math.Sin(angle2))
 
-		vector.StrokeLine(dst, x1, y1, x2, y2, 1, clr, true)
+		vector.StrokeLine(dst, x1, y1, x2, y2, strokeWidth, clr, true)
 	}
 }
 
@@ -423,74 +585,94 @@ func (g *Game) drawButton(screen *ebiten.Image, button Button) {
 }
 
 func (g *Game) drawGameOverScreen(screen *ebiten.Image) {
-	// Create overlay
-	overlayImg := ebiten.NewImage(screenWidth, screenHeight)
-	overlayImg.Fill(color.RGBA{0, 0, 0, 200})
+	l := g.layout
+	s := l.Scale
+	centerX := int(l.Width / 2)
+	centerY := int(l.Height / 2)
 
 	// Draw overlay
-	screen.DrawImage(overlayImg, nil)
+	drawOverlay(screen, l)
 
 	// Game over title
-	gameOverText := "Oyun Bitti!"
+	gameOverText := tr("gameOver")
 	w, _ := getBoundsSize(boldFont, gameOverText)
 	drawTextWithOptions(screen, gameOverText, boldFont,
-		(screenWidth-w)/2,
-		screenHeight/2-80,
+		centerX-w/2,
+		centerY-int(80*s),
 		colorTextLight)
 
 	// Game over reason
-	drawWrappedText(screen, g.gameOverReason, regularFont,
-		screenWidth/2-150, screenHeight/2-40,
-		300, colorTextLight)
+	drawWrappedText(screen, tr("ending."+g.endingID), regularFont,
+		centerX-int(150*s), centerY-int(40*s),
+		int(300*s), colorTextLight)
 
 	// Days lasted message
-	daysMessage := fmt.Sprintf("%d gün dayanabildiniz.", g.resources.Day-1)
+	daysMessage := fmt.Sprintf(tr("daysSurvived"), g.resources.Day-1)
 	w, _ = getBoundsSize(regularFont, daysMessage)
 	drawTextWithOptions(screen, daysMessage, regularFont,
-		(screenWidth-w)/2,
-		screenHeight/2+20,
+		centerX-w/2,
+		centerY+int(20*s),
 		colorTextLight)
 
+	// Personal best
+	bestMessage := fmt.Sprintf(tr("personalBest"), g.stats.BestDays())
+	if g.newPersonalBest {
+		bestMessage = tr("newPersonalBest")
+	}
+	w, _ = getBoundsSize(smallFont, bestMessage)
+	drawTextWithOptions(screen, bestMessage, smallFont,
+		centerX-w/2,
+		centerY+int(40*s),
+		colorTextLight)
+
+	// First time this ending was reached
+	if g.newEnding {
+		endingMessage := fmt.Sprintf(tr("gallery.newEnding"), g.endings.Discovered(), len(allEndings))
+		w, _ = getBoundsSize(smallFont, endingMessage)
+		drawTextWithOptions(screen, endingMessage, smallFont,
+			centerX-w/2,
+			centerY+int(58*s),
+			colorTextLight)
+	}
+
 	// Draw restart button
 	g.drawButton(screen, g.restartButton)
 }
 
 func (g *Game) drawAboutScreen(screen *ebiten.Image) {
-	// Create overlay
-	overlayImg := ebiten.NewImage(screenWidth, screenHeight)
-	overlayImg.Fill(color.RGBA{0, 0, 0, 200})
+	l := g.layout
 
 	// Draw overlay
-	screen.DrawImage(overlayImg, nil)
+	drawOverlay(screen, l)
 
 	// About container
-	aboutWidth := 500.0
-	aboutHeight := 350.0
-	aboutX := (screenWidth - aboutWidth) / 2
-	aboutY := (screenHeight - aboutHeight) / 2
+	aboutWidth := 500.0 * l.Scale
+	aboutHeight := 350.0 * l.Scale
+	aboutX := (l.Width - aboutWidth) / 2
+	aboutY := (l.Height - aboutHeight) / 2
 
 	// Draw container background
-	vector.DrawFilledRect(screen, float32(aboutX), float32(aboutY), float32(aboutWidth), float32(aboutHeight), colorCardBorder, true)
+	vector.DrawFilledRect(screen, float32(aboutX), float32(aboutY), float32(aboutWidth), float32(aboutHeight), colorPanel, true)
 
 	// Title
-	aboutTitleText := "Office Politics Hakkında"
+	aboutTitleText := tr("about.title")
 	w, _ := getBoundsSize(boldFont, aboutTitleText)
 	drawTextWithOptions(screen, aboutTitleText, boldFont,
-		(screenWidth-w)/2,
-		int(aboutY)+40,
+		int(l.Width)/2-w/2,
+		int(aboutY+40*l.Scale),
 		colorTextLight)
 
 	// Text content
-	aboutContent := "Bu oyun, Reigns oyunundan ilham alınarak yapılmıştır.\n\n" +
-		"Office Politics, ofis hayatındaki kararları simüle eden bir oyundur.\n" +
-		"Kartları sağa veya sola kaydırarak kararlar verin ve\n" +
-		"(M) motivasyon, (P) performans, (A) iş arkadaşları ve (P) patron memnuniyetini\n" +
-		"dengelemeye çalışarak oyunu kazanmaya çalışın.\n\n" +
-		"Kapatmak için herhangi bir yere tıklayın."
+	aboutContent := tr("about.body")
 
 	drawWrappedText(screen, aboutContent, regularFont,
-		int(aboutX)+30, int(aboutY)+80,
-		int(aboutWidth)-60, colorTextLight)
+		int(aboutX+30*l.Scale), int(aboutY+80*l.Scale),
+		int(aboutWidth-60*l.Scale), colorTextLight)
+}
+
+// drawOverlay dims the whole window behind a modal
+func drawOverlay(screen *ebiten.Image, l Layout) {
+	vector.DrawFilledRect(screen, 0, 0, float32(l.Width), float32(l.Height), colorOverlay, false)
 }
 
 func drawTextWithOptions(screen *ebiten.Image, str string, face font.Face, x, y int, clr color.Color) {
@@ -507,7 +689,7 @@ func getBoundsSize(face font.Face, str string) (width, height int) {
 
 // Draw wrapped text with updated API
 func drawWrappedText(screen *ebiten.Image, textContent string, fontFace font.Face, x, y, width int, clr color.Color) {
-	const lineHeight = 24
+	lineHeight := lineHeightFor(fontFace)
 
 	lines := wrapText(textContent, fontFace, width)
 	for i, line := range lines {
@@ -515,6 +697,12 @@ func drawWrappedText(screen *ebiten.Image, textContent string, fontFace font.Fac
 	}
 }
 
+// lineHeightFor returns the distance between wrapped lines for a face
+func lineHeightFor(fontFace font.Face) int {
+	const lineSpacing = 1.3
+	return int(float64(fontFace.Metrics().Height) / 64 * lineSpacing)
+}
+
 // Wraps text to fit within given width with updated bounds calculation
 func wrapText(content string, fontFace font.Face, width int) []string {
 	var result []string
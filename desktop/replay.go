@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	replayVersion = 1
+	replayDir     = "replays"
+
+	// Ticks between two choices at normal playback speed
+	replayStepTicks = 60
+	replayMinSpeed  = 0.25
+	replayMaxSpeed  = 8
+)
+
+// Choice letters stored in a replay
+const (
+	replayYes  = 'Y'
+	replayNo   = 'N'
+	replayInfo = 'I'
+)
+
+// Replay is everything needed to play a run again: the run is fully
+// determined by the deck, the seed, the starting stats and the choices
+type Replay struct {
+	Version    int       `json:"version"`
+	DeckHash   string    `json:"deckHash"`
+	Seed       int64     `json:"seed"`
+	Difficulty string    `json:"difficulty"`
+	Mode       string    `json:"mode"`
+	Start      Resources `json:"start"`
+	Cards      []string  `json:"cards"`   // Card shown at each step, to detect divergence
+	Choices    string    `json:"choices"` // One letter per step
+}
+
+// replayPlayer drives a run from a replay instead of player input
+type replayPlayer struct {
+	replay   *Replay
+	step     int
+	speed    float64
+	paused   bool
+	stepOnce bool
+	ticks    int
+	err      error
+}
+
+func loadReplay(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var replay Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return nil, err
+	}
+	if replay.Version > replayVersion {
+		return nil, fmt.Errorf("replay version %d is newer than supported version %d", replay.Version, replayVersion)
+	}
+	if len(replay.Cards) != len(replay.Choices) {
+		return nil, fmt.Errorf("replay has %d cards but %d choices", len(replay.Cards), len(replay.Choices))
+	}
+	return &replay, nil
+}
+
+// recordStep adds the answer to the current card to the run's replay
+func (g *Game) recordStep(isYes bool) {
+	choice := replayNo
+	if g.currentCard.IsInfoOnly {
+		choice = replayInfo
+	} else if isYes {
+		choice = replayYes
+	}
+	g.recording.Cards = append(g.recording.Cards, g.currentCard.ID)
+	g.recording.Choices += string(choice)
+}
+
+// saveReplay writes the finished run to the replays directory
+func (g *Game) saveReplay() {
+	g.recording.DeckHash = g.deckHash
+	name := g.replayFileName()
+	if err := saveUserJSON(name, g.recording); err != nil {
+		log.Printf("Failed to save replay: %v", err)
+		return
+	}
+	g.replayFile = name
+}
+
+// replayFileName names the replay of a run ending now. The seed tells
+// apart runs ending in the same second, a counter the rare ones that also
+// share the seed.
+func (g *Game) replayFileName() string {
+	base := filepath.Join(replayDir, fmt.Sprintf("%s-%x", g.clock.Now().Format("20060102-150405"), uint64(g.seed)))
+	name := profileFile(base + ".json")
+	for i := 2; ; i++ {
+		path, err := userDataPath(name)
+		if err != nil {
+			return name
+		}
+		if _, err := os.Stat(path); err != nil {
+			return name
+		}
+		name = profileFile(fmt.Sprintf("%s-%d.json", base, i))
+	}
+}
+
+// startReplay restarts the game and plays the replay back
+func (g *Game) startReplay(replay *Replay) error {
+	if replay.DeckHash != g.deckHash {
+		return fmt.Errorf("replay was recorded with deck %s but deck %s is loaded", replay.DeckHash, g.deckHash)
+	}
+
+	g.difficulty = replay.Difficulty
+	g.mode = replay.Mode
+	g.resetRun(replay.Seed, replay.Start)
+	g.player = &replayPlayer{replay: replay, speed: 1}
+	return nil
+}
+
+// updateReplay handles the playback controls and feeds the next choice
+// once the previous card has been resolved
+func (g *Game) updateReplay() {
+	p := g.player
+	if p == nil {
+		return
+	}
+
+	// Controls
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		p.paused = !p.paused
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyRight) || inpututil.IsKeyJustPressed(ebiten.KeyN) {
+		p.paused = true
+		p.stepOnce = true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) || inpututil.IsKeyJustPressed(ebiten.KeyKPAdd) {
+		p.speed = min(p.speed*2, replayMaxSpeed)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) || inpututil.IsKeyJustPressed(ebiten.KeyKPSubtract) {
+		p.speed = max(p.speed/2, replayMinSpeed)
+	}
+
+	if p.err != nil || g.animating || g.state != stateGame {
+		return
+	}
+
+	// The run must last exactly as many steps as were recorded
+	if p.step >= len(p.replay.Choices) {
+		if !g.gameOver {
+			g.failReplay(fmt.Errorf("replay ended after %d steps but the run is still going", p.step))
+		}
+		return
+	}
+	if g.gameOver {
+		g.failReplay(fmt.Errorf("run ended at step %d of %d", p.step, len(p.replay.Choices)))
+		return
+	}
+
+	if p.paused && !p.stepOnce {
+		return
+	}
+	p.ticks++
+	if !p.stepOnce && float64(p.ticks) < replayStepTicks/p.speed {
+		return
+	}
+	p.ticks = 0
+	p.stepOnce = false
+
+	isYes, err := g.nextReplayChoice()
+	if err != nil {
+		g.failReplay(err)
+		return
+	}
+	g.animateCardAway(isYes)
+}
+
+// nextReplayChoice returns the recorded answer to the current card and
+// moves to the next step. The re-simulated card must match the recorded
+// one, otherwise the run has diverged.
+func (g *Game) nextReplayChoice() (isYes bool, err error) {
+	p := g.player
+	expected := p.replay.Cards[p.step]
+	actual := ""
+	if g.currentCard != nil {
+		actual = g.currentCard.ID
+	}
+	if actual != expected {
+		return false, fmt.Errorf("diverged at step %d: recorded card %s, got %s", p.step+1, expected, actual)
+	}
+
+	choice := p.replay.Choices[p.step]
+	p.step++
+	return choice != replayNo, nil
+}
+
+// failReplay stops playback and reports why
+func (g *Game) failReplay(err error) {
+	g.player.err = err
+	g.player.paused = true
+	log.Printf("Replay failed: %v", err)
+}
+
+func (g *Game) drawReplayHUD(screen *ebiten.Image) {
+	p := g.player
+	if p == nil {
+		return
+	}
+
+	s := g.layout.Scale
+	status := tr("replay.playing")
+	if p.paused {
+		status = tr("replay.paused")
+	}
+	lines := []string{
+		fmt.Sprintf(tr("replay.status"), status, p.step, len(p.replay.Choices), formatMultiplier(p.speed)),
+		tr("replay.controls"),
+	}
+
+	// A failed replay is shown in a banner that can't be missed
+	clr := colorPanel
+	if p.err != nil {
+		clr = colorNoOption
+		lines = append(lines, tr("replay.failed"), p.err.Error())
+	}
+
+	height := float64(len(lines))*float64(lineHeightFor(smallFont)) + 16*s
+	width := min(g.layout.Width-20*s, 420*s)
+	vector.DrawFilledRect(screen, float32(10*s), float32(g.layout.Height-height-30*s), float32(width), float32(height), clr, true)
+	drawWrappedText(screen, strings.Join(lines, "\n"), smallFont,
+		int(20*s), int(g.layout.Height-height-30*s+20*s), int(width-20*s), colorTextLight)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// Cards that leave the stats alone, so a run goes on as long as needed
+const replayTestDeck = `{"schemaVersion": 2, "cards": [
+	{"id": "A", "text": "a", "maxUses": 5, "yesFollowups": [{"id": "AF", "text": "af", "delay": 2}]},
+	{"id": "B", "text": "b", "maxUses": 5},
+	{"id": "C", "text": "c", "maxUses": 5},
+	{"id": "D", "text": "d", "maxUses": 5, "noFollowups": [{"id": "DF", "text": "df", "isInfoOnly": true}]},
+	{"id": "E", "text": "e", "maxUses": 5}
+]}`
+
+// newReplayTestGame loads the test deck into a game that has not started a run
+func newReplayTestGame(t *testing.T) *Game {
+	saved := assets
+	assets = fstest.MapFS{"deck.json": {Data: []byte(replayTestDeck)}}
+	t.Cleanup(func() { assets = saved })
+
+	g := &Game{}
+	if err := g.loadCards("deck.json"); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// recordTestRun plays a run from the seed, answering yes to every third card
+func recordTestRun(t *testing.T, seed int64, steps int) Replay {
+	g := newReplayTestGame(t)
+	g.resetRun(seed, runStart)
+	for i := range steps {
+		g.processCard(i%3 == 0)
+	}
+	if g.gameOver {
+		t.Fatal("test run ended early")
+	}
+	g.recording.DeckHash = g.deckHash
+	return g.recording
+}
+
+// playBack feeds the replay to a new game through processCard and returns
+// the first divergence
+func playBack(t *testing.T, replay Replay) (*Game, error) {
+	g := newReplayTestGame(t)
+	if err := g.startReplay(&replay); err != nil {
+		t.Fatal(err)
+	}
+	for g.player.step < len(replay.Choices) {
+		isYes, err := g.nextReplayChoice()
+		if err != nil {
+			return g, err
+		}
+		g.processCard(isYes)
+	}
+	return g, nil
+}
+
+func TestReplayPlayback(t *testing.T) {
+	const steps = 20
+	recorded := recordTestRun(t, 42, steps)
+	if len(recorded.Cards) != steps || len(recorded.Choices) != steps {
+		t.Fatalf("recorded %d cards and %d choices, want %d", len(recorded.Cards), len(recorded.Choices), steps)
+	}
+
+	tests := []struct {
+		name    string
+		change  func(r *Replay)
+		wantErr string // Prefix of the divergence, empty if the replay matches
+	}{
+		{"unchanged", func(r *Replay) {}, ""},
+		{"other card recorded", func(r *Replay) { r.Cards[5] = "X" }, "diverged at step 6: recorded card X, got "},
+		{"other answer recorded", func(r *Replay) {
+			// Answering no instead of yes skips the followup of A
+			for i, card := range r.Cards {
+				if card == "A" && r.Choices[i] == replayYes {
+					r.Choices = r.Choices[:i] + string(replayNo) + r.Choices[i+1:]
+					return
+				}
+			}
+			t.Fatal("no yes to A in the recorded run")
+		}, "diverged at step "},
+		{"other seed", func(r *Replay) { r.Seed++ }, "diverged at step "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay := recorded
+			replay.Cards = append([]string(nil), recorded.Cards...)
+			tt.change(&replay)
+
+			g, err := playBack(t, replay)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("playback failed: %v", err)
+				}
+				if g.recording.Choices != recorded.Choices || strings.Join(g.recording.Cards, " ") != strings.Join(recorded.Cards, " ") {
+					t.Errorf("playback recorded %v %s, want %v %s", g.recording.Cards, g.recording.Choices, recorded.Cards, recorded.Choices)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("error %v, want a divergence starting with %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReplayFileName(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AppData", t.TempDir())
+
+	now := time.Date(2026, 10, 18, 9, 30, 15, 0, time.UTC)
+	save := func(seed int64) string {
+		g := &Game{clock: fakeClock{now}, seed: seed}
+		g.saveReplay()
+		return filepath.Base(g.replayFile)
+	}
+
+	tests := []struct {
+		seed int64
+		want string
+	}{
+		{1, "20261018-093015-1.json"},
+		{-1, "20261018-093015-ffffffffffffffff.json"},
+		{1, "20261018-093015-1-2.json"},
+		{1, "20261018-093015-1-3.json"},
+	}
+	for _, tt := range tests {
+		if got := save(tt.seed); got != tt.want {
+			t.Errorf("seed %d saved as %s, want %s", tt.seed, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import "math/rand"
+
+// gameSource is the game's seeded random source. It counts the values it
+// has produced so its state can be saved and restored by replaying them.
+type gameSource struct {
+	seed  int64
+	draws uint64
+	src   rand.Source64
+}
+
+// rngState is a saved position of a gameSource
+type rngState struct {
+	seed  int64
+	draws uint64
+}
+
+func newGameSource(seed int64) *gameSource {
+	return &gameSource{seed: seed, src: rand.NewSource(seed).(rand.Source64)}
+}
+
+func (s *gameSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *gameSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+func (s *gameSource) Seed(seed int64) {
+	s.seed = seed
+	s.draws = 0
+	s.src.Seed(seed)
+}
+
+func (s *gameSource) state() rngState {
+	return rngState{seed: s.seed, draws: s.draws}
+}
+
+// restore rewinds or advances the source to a saved state
+func (s *gameSource) restore(state rngState) {
+	s.Seed(state.seed)
+	for s.draws < state.draws {
+		s.Int63()
+	}
+}
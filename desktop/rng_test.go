@@ -0,0 +1,72 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// drawSome uses the source the way the game does, through rand.Rand
+func drawSome(rng *rand.Rand, n int) []int64 {
+	var values []int64
+	for i := 0; i < n; i++ {
+		switch i % 4 {
+		case 0:
+			values = append(values, int64(rng.Intn(100)))
+		case 1:
+			values = append(values, int64(rng.Float64()*1e9))
+		case 2:
+			values = append(values, int64(rng.Uint64()>>1))
+		default:
+			perm := rng.Perm(5)
+			values = append(values, int64(perm[0]*10+perm[4]))
+		}
+	}
+	return values
+}
+
+func TestGameSourceRestore(t *testing.T) {
+	tests := []struct {
+		name   string
+		seed   int64
+		before int // Draws before the state is saved
+		after  int // Draws after it, compared once restored
+		extra  int // Draws between the comparison runs
+	}{
+		{"fresh source", 1, 0, 10, 0},
+		{"mid run", 42, 7, 20, 0},
+		{"rewind after more draws", 42, 7, 20, 50},
+		{"negative seed", -9, 3, 5, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newGameSource(tt.seed)
+			rng := rand.New(src)
+			drawSome(rng, tt.before)
+
+			saved := src.state()
+			want := drawSome(rng, tt.after)
+			drawSome(rng, tt.extra)
+
+			src.restore(saved)
+			if src.state() != saved {
+				t.Errorf("state after restore = %+v, want %+v", src.state(), saved)
+			}
+			if got := drawSome(rng, tt.after); !slices.Equal(got, want) {
+				t.Errorf("draws after restore = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestGameSourceMatchesSeed(t *testing.T) {
+	// A restored source draws like a new one with the same seed
+	a := newGameSource(7)
+	b := newGameSource(7)
+	drawSome(rand.New(a), 12)
+
+	a.restore(rngState{seed: 7})
+	if got, want := drawSome(rand.New(a), 12), drawSome(rand.New(b), 12); !slices.Equal(got, want) {
+		t.Errorf("draws = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Root types the schema command can describe
+var schemaKinds = map[string]reflect.Type{
+	"deck":     reflect.TypeOf(Deck{}),
+	"pack":     reflect.TypeOf(PackDeck{}),
+	"manifest": reflect.TypeOf(PackManifest{}),
+}
+
+// Descriptions of the deck types and fields, keyed by Go type name and
+// JSON field name
+var schemaDescriptions = map[string]string{
+	"Deck":                      "An Office Politics deck.",
+	"Deck.schemaVersion":        "Version of the deck format. Files without it are version 1 and are upgraded when loaded.",
+	"Deck.include":              "Deck files merged into this one, relative to it. Patterns like cards/*.json include every match.",
+	"Deck.characters":           "Recurring people who present cards.",
+	"Deck.achievements":         "Long-term goals tracked across runs.",
+	"Deck.cards":                "Cards shuffled into every run.",
+	"Deck.followupCards":        "Cards only shown as followups, named by a ref.",
+	"PackDeck":                  "The deck of a deck pack. New content is namespaced with the pack ID.",
+	"PackDeck.override":         "Cards replacing every card with the same ID in the base deck or an earlier pack.",
+	"PackDeck.patch":            "Fields to set on every card with the given ID. A null value removes the field.",
+	"PackDeck.remove":           "IDs of cards to remove, followups included.",
+	"PackManifest":              "Describes a deck pack.",
+	"PackManifest.id":           "Pack ID used as the namespace of its content. Defaults to the directory name.",
+	"PackManifest.version":      "Version of the pack, shown in the packs menu.",
+	"PackManifest.dependencies": "IDs of packs that must be enabled and are loaded first.",
+
+	"Card":              "A decision or info card.",
+	"Card.id":           "Unique card ID, used by followups, achievements and replays.",
+	"Card.text":         "Text shown on the card.",
+	"Card.yesText":      "Label of the yes answer.",
+	"Card.noText":       "Label of the no answer.",
+	"Card.yesEffects":   "Stat changes when the player answers yes.",
+	"Card.noEffects":    "Stat changes when the player answers no.",
+	"Card.isInfoOnly":   "Info cards have a single answer and use effects and followups.",
+	"Card.effects":      "Stat changes of an info card.",
+	"Card.requirements": "Condition on the stats for the card to be drawn.",
+	"Card.maxUses":      "How many times the card can appear in one shuffle. Defaults to 1, 0 disables the card.",
+	"Card.parentCardId": "Set at runtime on followups to the card that queued them.",
+	"Card.image":        "Illustration, relative to the deck directory.",
+	"Card.speaker":      "ID of the character presenting the card.",
+	"Card.sound":        "Sound played when the card appears, relative to the deck directory.",
+	"Card.yesFollowups": "Candidates queued after a yes answer, one is picked by probability.",
+	"Card.noFollowups":  "Candidates queued after a no answer, one is picked by probability.",
+	"Card.followups":    "Candidates queued after an info card, one is picked by probability.",
+	"Card.delay":        "Days until a followup is shown, 0 for the next card. Defaults to 1.",
+	"Card.probability":  "Relative weight of a followup among the other candidates. Without weights candidates are picked uniformly.",
+	"Card.ref":          "Shows the card with this ID, defined at the top of any deck file, as a followup. Only delay and probability may be set next to it.",
+
+	"Requirement":            "A stat comparison, or an and/or of other requirements.",
+	"Requirement.type":       "Combines conditions; leave out for a simple comparison.",
+	"Requirement.conditions": "Requirements combined by type.",
+	"Requirement.resource":   "Stat to compare.",
+	"Requirement.comparison": "How the stat is compared with value.",
+	"Requirement.value":      "Value the stat is compared with.",
+
+	"Effects": "Changes to the stats, added before clamping to 0-100.",
+
+	"Character":          "A recurring person who presents cards.",
+	"Character.portrait": "Portrait image, relative to the deck directory.",
+	"Character.color":    "Accent color as #rrggbb or #rrggbbaa.",
+
+	"Achievement":                      "A long-term goal.",
+	"AchievementCondition":             "Checked after every card. Type decides which other fields are used.",
+	"AchievementCondition.conditions":  "Conditions combined by an and or or condition.",
+	"AchievementCondition.value":       "Days survived or streak length.",
+	"AchievementCondition.choice":      "Answer counted by a choice streak.",
+	"AchievementCondition.speaker":     "Character ID a choice streak is counted for.",
+	"AchievementCondition.cards":       "Card IDs played in this order, not necessarily back to back.",
+	"AchievementCondition.ending":      "Ending the run has to reach.",
+	"AchievementCondition.requirement": "Condition on the stats.",
+}
+
+// Allowed values of string fields
+var schemaEnums = map[string][]string{
+	"Requirement.type":            requirementTypes,
+	"Requirement.resource":        requirementResources,
+	"Requirement.comparison":      requirementComparisons,
+	"AchievementCondition.type":   achievementConditionTypes,
+	"AchievementCondition.choice": {choiceYes, choiceNo},
+	"AchievementCondition.ending": allEndings,
+}
+
+// Lower bounds of number fields
+var schemaMinimums = map[string]int{
+	"Deck.schemaVersion": 1,
+	"Card.maxUses":       0,
+	"Card.delay":         0,
+	"Card.probability":   0,
+}
+
+// Fields every object of a type must have
+var schemaRequired = map[string][]string{
+	"Character":            {"id", "name"},
+	"Achievement":          {"id", "name", "condition"},
+	"AchievementCondition": {"type"},
+	"PackManifest":         {"name", "version"},
+}
+
+// Types whose objects must have all fields of one of the sets
+var schemaRequiredAny = map[string][][]string{
+	"Card": {{"id", "text"}, {"ref"}},
+}
+
+// deckSchema builds a JSON Schema for one of the schema kinds from the Go
+// types, so it can't drift from what the loader accepts
+func deckSchema(kind string) (*jsonObject, error) {
+	root, ok := schemaKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %q", kind)
+	}
+
+	gen := &schemaGenerator{defs: newJSONObject()}
+	ref := gen.typeSchema(root)
+
+	schema := newJSONObject()
+	schema.Set("$schema", jsonSchemaDialect)
+	schema.Set("title", "Office Politics "+kind)
+	if kind == "deck" {
+		// Version 1 decks may still be a plain array of cards
+		legacy := newJSONObject()
+		legacy.Set("type", "array")
+		legacy.Set("description", "Version 1 deck: a plain array of cards.")
+		legacy.Set("items", gen.typeSchema(reflect.TypeOf(Card{})))
+		schema.Set("anyOf", []any{ref, legacy})
+	} else {
+		schema.Set("$ref", ref.values["$ref"])
+	}
+	schema.Set("$defs", gen.defs)
+	return schema, nil
+}
+
+type schemaGenerator struct {
+	defs *jsonObject
+}
+
+func (g *schemaGenerator) typeSchema(t reflect.Type) *jsonObject {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	s := newJSONObject()
+	switch {
+	case t == reflect.TypeOf(HexColor{}):
+		s.Set("type", "string")
+		s.Set("pattern", "^#([0-9a-fA-F]{6}|[0-9a-fA-F]{8})$")
+		return s
+	case t == reflect.TypeOf(json.RawMessage{}):
+		// Only patches are kept raw: any card fields, but always an ID
+		id := newJSONObject()
+		id.Set("type", "string")
+		properties := newJSONObject()
+		properties.Set("id", id)
+		s.Set("type", "object")
+		s.Set("properties", properties)
+		s.Set("required", []any{"id"})
+		return s
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if _, ok := g.defs.Get(t.Name()); !ok {
+			// Placeholder first so recursive types refer back to it
+			g.defs.Set(t.Name(), nil)
+			g.defs.Set(t.Name(), g.structSchema(t))
+		}
+		s.Set("$ref", "#/$defs/"+t.Name())
+	case reflect.Slice:
+		s.Set("type", "array")
+		s.Set("items", g.typeSchema(t.Elem()))
+	case reflect.Map:
+		s.Set("type", "object")
+		s.Set("additionalProperties", g.typeSchema(t.Elem()))
+	case reflect.String:
+		s.Set("type", "string")
+	case reflect.Bool:
+		s.Set("type", "boolean")
+	case reflect.Int, reflect.Int64:
+		s.Set("type", "integer")
+	case reflect.Float64:
+		s.Set("type", "number")
+	}
+	return s
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *jsonObject {
+	s := newJSONObject()
+	s.Set("type", "object")
+	if desc, ok := schemaDescriptions[t.Name()]; ok {
+		s.Set("description", desc)
+	}
+
+	properties := newJSONObject()
+	g.addFields(t, t.Name(), properties)
+	s.Set("properties", properties)
+
+	if required, ok := schemaRequired[t.Name()]; ok {
+		s.Set("required", schemaNames(required))
+	}
+	if sets, ok := schemaRequiredAny[t.Name()]; ok {
+		var anyOf []any
+		for _, required := range sets {
+			alternative := newJSONObject()
+			alternative.Set("required", schemaNames(required))
+			anyOf = append(anyOf, alternative)
+		}
+		s.Set("anyOf", anyOf)
+	}
+	s.Set("additionalProperties", false)
+	return s
+}
+
+func schemaNames(names []string) []any {
+	list := make([]any, len(names))
+	for i, name := range names {
+		list[i] = name
+	}
+	return list
+}
+
+// addFields adds a property per JSON field, flattening embedded structs
+func (g *schemaGenerator) addFields(t reflect.Type, owner string, properties *jsonObject) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		if field.Anonymous && tag == "" {
+			g.addFields(field.Type, field.Type.Name(), properties)
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		key := owner + "." + name
+
+		property := g.typeSchema(field.Type)
+		if desc, ok := schemaDescriptions[key]; ok {
+			property.Set("description", desc)
+		}
+		if values, ok := schemaEnums[key]; ok {
+			var enum []any
+			for _, v := range values {
+				enum = append(enum, v)
+			}
+			property.Set("enum", enum)
+		}
+		if minimum, ok := schemaMinimums[key]; ok {
+			property.Set("minimum", json.Number(strconv.Itoa(minimum)))
+		}
+		if key == "Deck.schemaVersion" {
+			property.Set("maximum", json.Number(strconv.Itoa(deckSchemaVersion)))
+		}
+		properties.Set(name, property)
+	}
+}
+
+func runSchema(args []string, _ string) error {
+	flags := flag.NewFlagSet("schema", flag.ContinueOnError)
+	kind := flags.String("kind", "deck", "What to describe: deck, pack or manifest")
+	output := flags.String("o", "", "Write the schema to this file instead of standard output")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	schema, err := deckSchema(*kind)
+	if err != nil {
+		return err
+	}
+	data, err := encodeJSONDocument(schema)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0o644)
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const settingsFile = "settings.json"
+
+// Settings are the player's preferences, saved in the user config directory
+type Settings struct {
+	Language         string        `json:"language"`
+	Theme            string        `json:"theme"`
+	Audio            AudioSettings `json:"audio"`
+	AnimationSpeed   float64       `json:"animationSpeed"`   // Multiplier on card animation speed
+	ReducedMotion    bool          `json:"reducedMotion"`    // Fade cards instead of flying and rotating them
+	TextSize         float64       `json:"textSize"`         // Multiplier on font sizes
+	SwipeSensitivity float64       `json:"swipeSensitivity"` // Higher values need a shorter drag to swipe
+	Casual           bool          `json:"casual"`           // New runs allow undoing decisions
+	UndoLimit        int           `json:"undoLimit"`        // Undos per run in casual mode, 0 for unlimited
+	Packs            []string      `json:"packs,omitempty"`  // IDs of the enabled deck packs
+}
+
+func defaultSettings() Settings {
+	return Settings{
+		Language: "tr",
+		Theme:    "light",
+		Audio: AudioSettings{
+			SFXVolume:   0.8,
+			MusicVolume: 0.4,
+		},
+		AnimationSpeed:   1,
+		TextSize:         1,
+		SwipeSensitivity: 1,
+		UndoLimit:        3,
+	}
+}
+
+// loadSettings reads saved settings over the defaults
+func loadSettings() Settings {
+	settings := defaultSettings()
+	if err := loadUserJSON(profileFile(settingsFile), &settings); err != nil {
+		log.Printf("Failed to load settings: %v", err)
+		return defaultSettings()
+	}
+
+	// Multipliers must stay positive, a hand-edited file may break them
+	defaults := defaultSettings()
+	if settings.AnimationSpeed <= 0 {
+		settings.AnimationSpeed = defaults.AnimationSpeed
+	}
+	if settings.TextSize <= 0 {
+		settings.TextSize = defaults.TextSize
+	}
+	if settings.SwipeSensitivity <= 0 {
+		settings.SwipeSensitivity = defaults.SwipeSensitivity
+	}
+	if settings.UndoLimit < 0 {
+		settings.UndoLimit = defaults.UndoLimit
+	}
+	return settings
+}
+
+func (g *Game) saveSettings() {
+	if err := saveUserJSON(profileFile(settingsFile), g.settings); err != nil {
+		log.Printf("Failed to save settings: %v", err)
+	}
+}
+
+// applySettings makes the current settings take effect immediately
+func (g *Game) applySettings() {
+	currentLanguage = g.settings.Language
+	g.restartButton.Text = tr("restart")
+
+	// Theme, falling back to the first one if the saved theme is gone
+	g.themeIndex = 0
+	for i, theme := range g.themes {
+		if theme.ID == g.settings.Theme {
+			g.themeIndex = i
+		}
+	}
+	g.applyTheme(g.themes[g.themeIndex])
+
+	g.audio.SetSettings(g.settings.Audio)
+
+	if err := setFontScale(g.fontScale()); err != nil {
+		log.Printf("Failed to rescale fonts: %v", err)
+	}
+}
+
+// fontScale combines the layout scale with the text size setting
+func (g *Game) fontScale() float64 {
+	return g.layout.Scale * g.settings.TextSize
+}
+
+// animSpeed is the fraction of the remaining distance animations cover
+// each frame
+func (g *Game) animSpeed() float64 {
+	return math.Min(0.1*g.animationSpeed(), 1)
+}
+
+// animationSpeed is the animation speed setting, sped up or slowed down
+// further while watching a replay
+func (g *Game) animationSpeed() float64 {
+	if g.player != nil {
+		return g.settings.AnimationSpeed * g.player.speed
+	}
+	return g.settings.AnimationSpeed
+}
+
+// swipeDistance is how far the card must be dragged to count as a swipe
+func (g *Game) swipeDistance() float64 {
+	return swipeThreshold * g.layout.Scale / g.settings.SwipeSensitivity
+}
+
+// settingRow is one adjustable line on the settings screen
+type settingRow struct {
+	label  string
+	value  func(g *Game) string
+	change func(g *Game, dir int)
+}
+
+var settingRows = []settingRow{
+	{
+		label: "settings.language",
+		value: func(g *Game) string {
+			for _, lang := range languages {
+				if lang.Code == g.settings.Language {
+					return lang.Name
+				}
+			}
+			return g.settings.Language
+		},
+		change: func(g *Game, dir int) {
+			i := 0
+			for j, lang := range languages {
+				if lang.Code == g.settings.Language {
+					i = j
+				}
+			}
+			g.settings.Language = languages[wrapIndex(i+dir, len(languages))].Code
+		},
+	},
+	{
+		label: "settings.theme",
+		value: func(g *Game) string { return g.themes[g.themeIndex].Name },
+		change: func(g *Game, dir int) {
+			g.settings.Theme = g.themes[wrapIndex(g.themeIndex+dir, len(g.themes))].ID
+		},
+	},
+	{
+		label: "settings.sfxVolume",
+		value: func(g *Game) string { return formatPercent(g.settings.Audio.SFXVolume) },
+		change: func(g *Game, dir int) {
+			g.settings.Audio.SFXVolume = stepValue(g.settings.Audio.SFXVolume, 0.1, 0, 1, dir)
+		},
+	},
+	{
+		label: "settings.musicVolume",
+		value: func(g *Game) string { return formatPercent(g.settings.Audio.MusicVolume) },
+		change: func(g *Game, dir int) {
+			g.settings.Audio.MusicVolume = stepValue(g.settings.Audio.MusicVolume, 0.1, 0, 1, dir)
+		},
+	},
+	{
+		label:  "settings.muted",
+		value:  func(g *Game) string { return formatToggle(g.settings.Audio.Muted) },
+		change: func(g *Game, _ int) { g.settings.Audio.Muted = !g.settings.Audio.Muted },
+	},
+	{
+		label: "settings.animationSpeed",
+		value: func(g *Game) string { return formatMultiplier(g.settings.AnimationSpeed) },
+		change: func(g *Game, dir int) {
+			g.settings.AnimationSpeed = stepValue(g.settings.AnimationSpeed, 0.25, 0.5, 2, dir)
+		},
+	},
+	{
+		label:  "settings.reducedMotion",
+		value:  func(g *Game) string { return formatToggle(g.settings.ReducedMotion) },
+		change: func(g *Game, _ int) { g.settings.ReducedMotion = !g.settings.ReducedMotion },
+	},
+	{
+		label: "settings.textSize",
+		value: func(g *Game) string { return formatMultiplier(g.settings.TextSize) },
+		change: func(g *Game, dir int) {
+			g.settings.TextSize = stepValue(g.settings.TextSize, 0.1, 0.8, 1.5, dir)
+		},
+	},
+	{
+		label: "settings.swipeSensitivity",
+		value: func(g *Game) string { return formatMultiplier(g.settings.SwipeSensitivity) },
+		change: func(g *Game, dir int) {
+			g.settings.SwipeSensitivity = stepValue(g.settings.SwipeSensitivity, 0.25, 0.5, 2, dir)
+		},
+	},
+	{
+		label:  "settings.casual",
+		value:  func(g *Game) string { return formatToggle(g.settings.Casual) },
+		change: func(g *Game, _ int) { g.settings.Casual = !g.settings.Casual },
+	},
+	{
+		label: "settings.undoLimit",
+		value: func(g *Game) string {
+			if g.settings.UndoLimit == 0 {
+				return "∞"
+			}
+			return strconv.Itoa(g.settings.UndoLimit)
+		},
+		change: func(g *Game, dir int) {
+			i := 0
+			for j, limit := range undoLimits {
+				if limit == g.settings.UndoLimit {
+					i = j
+				}
+			}
+			g.settings.UndoLimit = undoLimits[wrapIndex(i+dir, len(undoLimits))]
+		},
+	},
+}
+
+// settingsRowRects are the hit areas of one settings row
+type settingsRowRects struct {
+	Label, Prev, Value, Next Rect
+}
+
+// settingsGeometry lays out the settings panel. Drawing and input both use
+// it so they never disagree.
+func (g *Game) settingsGeometry() (panel Rect, rows []settingsRowRects, closeButton Rect) {
+	l := g.layout
+	s := l.Scale
+	rowHeight := 44 * s
+	arrowSize := 32 * s
+	valueWidth := 150 * s
+
+	width := math.Min(480*s, l.Width-20*s)
+	height := 70*s + float64(len(settingRows))*rowHeight + 80*s
+	panel = Rect{X: (l.Width - width) / 2, Y: (l.Height - height) / 2, Width: width, Height: height}
+
+	y := panel.Y + 70*s
+	for range settingRows {
+		next := Rect{X: panel.X + width - 20*s - arrowSize, Y: y + (rowHeight-arrowSize)/2, Width: arrowSize, Height: arrowSize}
+		value := Rect{X: next.X - valueWidth, Y: y, Width: valueWidth, Height: rowHeight}
+		prev := Rect{X: value.X - arrowSize, Y: next.Y, Width: arrowSize, Height: arrowSize}
+		label := Rect{X: panel.X + 20*s, Y: y, Width: prev.X - panel.X - 30*s, Height: rowHeight}
+		rows = append(rows, settingsRowRects{Label: label, Prev: prev, Value: value, Next: next})
+		y += rowHeight
+	}
+
+	closeButton = Rect{X: panel.CenterX() - 80*s, Y: y + 20*s, Width: 160 * s, Height: 44 * s}
+	return panel, rows, closeButton
+}
+
+// updateSettings handles input on the settings screen
+func (g *Game) updateSettings() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.returnToGame()
+		return
+	}
+
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+
+	mx, my := ebiten.CursorPosition()
+	x, y := float64(mx), float64(my)
+	panel, rows, closeButton := g.settingsGeometry()
+
+	// Close button, or click outside the panel
+	if closeButton.Contains(x, y) || !panel.Contains(x, y) {
+		g.returnToGame()
+		return
+	}
+
+	for i, row := range rows {
+		dir := 0
+		if row.Prev.Contains(x, y) {
+			dir = -1
+		} else if row.Next.Contains(x, y) || row.Value.Contains(x, y) {
+			dir = 1
+		}
+		if dir == 0 {
+			continue
+		}
+
+		settingRows[i].change(g, dir)
+		g.applySettings()
+		g.saveSettings()
+		return
+	}
+}
+
+func (g *Game) drawSettingsScreen(screen *ebiten.Image) {
+	l := g.layout
+	s := l.Scale
+	panel, rows, closeButton := g.settingsGeometry()
+
+	// Draw overlay and panel
+	drawOverlay(screen, l)
+	vector.DrawFilledRect(screen, float32(panel.X), float32(panel.Y), float32(panel.Width), float32(panel.Height), colorPanel, true)
+
+	// Title
+	title := tr("settings.title")
+	w, _ := getBoundsSize(boldFont, title)
+	drawTextWithOptions(screen, title, boldFont, int(panel.CenterX())-w/2, int(panel.Y+45*s), colorTextLight)
+
+	for i, row := range rows {
+		setting := settingRows[i]
+		_, h := getBoundsSize(regularFont, "Ag")
+		baseline := int(row.Label.CenterY()) + h/2
+
+		// Label
+		drawTextWithOptions(screen, tr(setting.label), regularFont, int(row.Label.X), baseline, colorTextLight)
+
+		// Arrows
+		for _, arrow := range []struct {
+			rect Rect
+			text string
+		}{{row.Prev, "‹"}, {row.Next, "›"}} {
+			g.drawButton(screen, Button{
+				X: arrow.rect.X, Y: arrow.rect.Y, Width: arrow.rect.Width, Height: arrow.rect.Height,
+				Text: arrow.text, Color: colorAboutHover, TextColor: colorTextLight,
+			})
+		}
+
+		// Value
+		value := setting.value(g)
+		w, _ := getBoundsSize(regularFont, value)
+		drawTextWithOptions(screen, value, regularFont, int(row.Value.CenterX())-w/2, baseline, colorTextLight)
+	}
+
+	// Close button
+	mx, my := ebiten.CursorPosition()
+	g.drawButton(screen, Button{
+		X: closeButton.X, Y: closeButton.Y, Width: closeButton.Width, Height: closeButton.Height,
+		Text: tr("settings.close"), Color: colorRestartBtn, HoverColor: colorRestartHover, TextColor: colorTextLight,
+		IsHovered: closeButton.Contains(float64(mx), float64(my)),
+	})
+}
+
+// stepValue moves v by one step in the given direction, keeping it in range
+func stepValue(v, step, min, max float64, dir int) float64 {
+	v = math.Round((v+step*float64(dir))*100) / 100
+	return math.Max(min, math.Min(max, v))
+}
+
+// wrapIndex wraps i into [0, n)
+func wrapIndex(i, n int) int {
+	return ((i % n) + n) % n
+}
+
+func formatPercent(v float64) string {
+	return fmt.Sprintf("%d%%", int(math.Round(v*100)))
+}
+
+func formatMultiplier(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64) + "x"
+}
+
+func formatToggle(v bool) string {
+	if v {
+		return tr("on")
+	}
+	return tr("off")
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const statsFile = "stats.json"
+
+// RunRecord is one finished run
+type RunRecord struct {
+	FinishedAt time.Time `json:"finishedAt"`
+	Days       int       `json:"days"`
+	Ending     string    `json:"ending"`
+	Mode       string    `json:"mode"`
+	Difficulty string    `json:"difficulty"`
+	Won        bool      `json:"won"`
+	Seed       int64     `json:"seed"`
+	DeckHash   string    `json:"deckHash"`
+	Final      Resources `json:"final"`
+	Replay     string    `json:"replay,omitempty"` // Replay file in the user data directory
+	Undos      int       `json:"undos,omitempty"`  // Decisions taken back in casual mode
+}
+
+// StatsStore keeps every finished run on this machine
+type StatsStore struct {
+	Runs []RunRecord `json:"runs"`
+}
+
+// StatsSummary is what the stats page shows
+type StatsSummary struct {
+	TotalRuns         int
+	TotalDays         int
+	AverageDays       float64
+	BestDays          int
+	Wins              int
+	BestWinStreak     int
+	CurrentWinStreak  int
+	CommonEnding      string
+	CommonEndingCount int
+	UndoRuns          int
+}
+
+func loadStats() *StatsStore {
+	store := &StatsStore{}
+	if err := loadUserJSON(profileFile(statsFile), store); err != nil {
+		log.Printf("Failed to load stats: %v", err)
+	}
+	return store
+}
+
+// BestDays returns the longest run so far
+func (s *StatsStore) BestDays() int {
+	best := 0
+	for _, run := range s.Runs {
+		best = max(best, run.Days)
+	}
+	return best
+}
+
+// Record adds a finished run and saves the store
+func (s *StatsStore) Record(run RunRecord) {
+	s.Runs = append(s.Runs, run)
+	if err := saveUserJSON(profileFile(statsFile), s); err != nil {
+		log.Printf("Failed to save stats: %v", err)
+	}
+}
+
+// Summary computes totals, averages and streaks over all runs
+func (s *StatsStore) Summary() StatsSummary {
+	var sum StatsSummary
+	endingCounts := make(map[string]int)
+	streak := 0
+
+	for _, run := range s.Runs {
+		sum.TotalRuns++
+		sum.TotalDays += run.Days
+		sum.BestDays = max(sum.BestDays, run.Days)
+		if run.Undos > 0 {
+			sum.UndoRuns++
+		}
+
+		if run.Won {
+			sum.Wins++
+			streak++
+			sum.BestWinStreak = max(sum.BestWinStreak, streak)
+		} else {
+			streak = 0
+			endingCounts[run.Ending]++
+		}
+	}
+	sum.CurrentWinStreak = streak
+
+	if sum.TotalRuns > 0 {
+		sum.AverageDays = float64(sum.TotalDays) / float64(sum.TotalRuns)
+	}
+
+	// Most common cause of termination, ties go to the first ending listed
+	for _, ending := range allEndings {
+		if count := endingCounts[ending]; count > sum.CommonEndingCount {
+			sum.CommonEnding = ending
+			sum.CommonEndingCount = count
+		}
+	}
+
+	return sum
+}
+
+// recordRun saves the run that just ended and checks for a personal best
+func (g *Game) recordRun() {
+	days := g.resources.Day - 1
+	previousBest := g.stats.BestDays()
+	g.newPersonalBest = days > previousBest && days > 0
+
+	g.stats.Record(RunRecord{
+		FinishedAt: g.clock.Now(),
+		Mode:       g.mode,
+		Difficulty: g.difficulty,
+		Days:       days,
+		Ending:     g.endingID,
+		Won:        g.gameWon,
+		Seed:       g.seed,
+		DeckHash:   g.deckHash,
+		Final:      g.resources,
+		Replay:     g.replayFile,
+		Undos:      g.undoCount,
+	})
+}
+
+func (g *Game) drawStatsScreen(screen *ebiten.Image) {
+	l := g.layout
+	s := l.Scale
+	sum := g.stats.Summary()
+
+	// Draw overlay and panel
+	drawOverlay(screen, l)
+	panel := Rect{X: (l.Width - 460*s) / 2, Y: (l.Height - 420*s) / 2, Width: 460 * s, Height: 420 * s}
+	vector.DrawFilledRect(screen, float32(panel.X), float32(panel.Y), float32(panel.Width), float32(panel.Height), colorPanel, true)
+
+	// Title
+	title := tr("stats.title")
+	w, _ := getBoundsSize(boldFont, title)
+	drawTextWithOptions(screen, title, boldFont, int(panel.CenterX())-w/2, int(panel.Y+45*s), colorTextLight)
+
+	if sum.TotalRuns == 0 {
+		drawWrappedText(screen, tr("stats.empty"), regularFont,
+			int(panel.X+30*s), int(panel.Y+100*s), int(panel.Width-60*s), colorTextLight)
+		return
+	}
+
+	commonEnding := "-"
+	if sum.CommonEnding != "" {
+		commonEnding = fmt.Sprintf("%s (%d)", tr("ending."+sum.CommonEnding), sum.CommonEndingCount)
+	}
+
+	lines := []string{
+		fmt.Sprintf(tr("stats.totalRuns"), sum.TotalRuns),
+		fmt.Sprintf(tr("stats.totalDays"), sum.TotalDays),
+		fmt.Sprintf(tr("stats.averageDays"), sum.AverageDays),
+		fmt.Sprintf(tr("stats.bestDays"), sum.BestDays),
+		fmt.Sprintf(tr("stats.wins"), sum.Wins),
+		fmt.Sprintf(tr("stats.winStreak"), sum.BestWinStreak, sum.CurrentWinStreak),
+		fmt.Sprintf(tr("stats.undoRuns"), sum.UndoRuns),
+		"",
+		tr("stats.commonEnding"),
+		commonEnding,
+	}
+	drawWrappedText(screen, strings.Join(lines, "\n"), regularFont,
+		int(panel.X+30*s), int(panel.Y+95*s), int(panel.Width-60*s), colorTextLight)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Directory name used under the OS user config directory
+const userDataDirName = "office-politics"
+
+// userDataPath returns the path of a file in the game's user data
+// directory, creating the directories if needed
+func userDataPath(name string) (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(base, userDataDirName, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// loadUserJSON decodes a user data file into v. A missing file is not an
+// error and leaves v untouched.
+func loadUserJSON(name string, v interface{}) error {
+	path, err := userDataPath(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// saveUserJSON writes v to a user data file, replacing it atomically so a
+// crash never leaves a half-written file behind
+func saveUserJSON(name string, v interface{}) error {
+	path, err := userDataPath(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic replaces a file through a temporary file, so an
+// interrupted write never leaves half a file behind
+func writeFileAtomic(filename string, data []byte) error {
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
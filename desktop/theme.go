@@ -0,0 +1,246 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// HexColor is a color written as "#rrggbb" or "#rrggbbaa" in theme files
+type HexColor color.RGBA
+
+func (c *HexColor) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := parseHexColor(s)
+	if err != nil {
+		return err
+	}
+
+	*c = HexColor(parsed)
+	return nil
+}
+
+func (c HexColor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A))
+}
+
+// parseHexColor parses "#rrggbb" or "#rrggbbaa"
+func parseHexColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+
+	return color.RGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+// ThemeColors is the palette a theme assigns to the UI
+type ThemeColors struct {
+	Background       HexColor `json:"background"`
+	Card             HexColor `json:"card"`
+	InfoCard         HexColor `json:"infoCard"`
+	CardBorder       HexColor `json:"cardBorder"`
+	InfoBorder       HexColor `json:"infoBorder"`
+	Motivation       HexColor `json:"motivation"`
+	Performance      HexColor `json:"performance"`
+	Colleagues       HexColor `json:"colleagues"`
+	Boss             HexColor `json:"boss"`
+	YesOption        HexColor `json:"yesOption"`
+	NoOption         HexColor `json:"noOption"`
+	SwipeHint        HexColor `json:"swipeHint"`
+	RestartButton    HexColor `json:"restartButton"`
+	RestartHover     HexColor `json:"restartButtonHover"`
+	AboutButton      HexColor `json:"aboutButton"`
+	AboutHover       HexColor `json:"aboutButtonHover"`
+	Panel            HexColor `json:"panel"`
+	Overlay          HexColor `json:"overlay"`
+	TextPrimary      HexColor `json:"textPrimary"`
+	TextLight        HexColor `json:"textLight"`
+	TextOnBackground HexColor `json:"textOnBackground"`
+}
+
+// ThemeFonts lists the font files a theme uses
+type ThemeFonts struct {
+	Text  string `json:"text"`
+	Emoji string `json:"emoji,omitempty"`
+}
+
+// ThemeShadow is a drop shadow drawn under the card
+type ThemeShadow struct {
+	Color   HexColor `json:"color"`
+	OffsetX float64  `json:"offsetX"`
+	OffsetY float64  `json:"offsetY"`
+}
+
+// Theme describes the look of the game
+type Theme struct {
+	ID               string      `json:"id"`
+	Name             string      `json:"name"`
+	Colors           ThemeColors `json:"colors"`
+	Fonts            ThemeFonts  `json:"fonts"`
+	CardCornerRadius float64     `json:"cardCornerRadius"`
+	BorderWidth      float64     `json:"borderWidth"`
+	Shadow           ThemeShadow `json:"shadow"`
+}
+
+// loadThemes reads the theme list, falling back to the built-in light
+// theme when the file is missing or invalid. Values a theme leaves out,
+// colours included, are taken from the light theme.
+func loadThemes(filename string) ([]*Theme, error) {
+	data, err := fs.ReadFile(assets, filename)
+	if err != nil {
+		return []*Theme{defaultTheme()}, err
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return []*Theme{defaultTheme()}, err
+	}
+
+	if len(raw) == 0 {
+		log.Print("Theme file contained no themes, using the default theme")
+		return []*Theme{defaultTheme()}, nil
+	}
+
+	themes := make([]*Theme, 0, len(raw))
+	for _, item := range raw {
+		theme := defaultTheme()
+		theme.ID, theme.Name = "", ""
+		if err := json.Unmarshal(item, theme); err != nil {
+			return []*Theme{defaultTheme()}, err
+		}
+		themes = append(themes, theme)
+	}
+
+	for _, theme := range themes {
+		if theme.Fonts.Text == "" {
+			theme.Fonts = defaultTheme().Fonts
+		}
+		if theme.BorderWidth <= 0 {
+			theme.BorderWidth = 1
+		}
+	}
+
+	return themes, nil
+}
+
+// defaultTheme is the original light look, used when no theme file loads
+func defaultTheme() *Theme {
+	return &Theme{
+		ID:   "light",
+		Name: "Açık",
+		Colors: ThemeColors{
+			Background:       HexColor{243, 244, 246, 255},
+			Card:             HexColor{255, 255, 255, 255},
+			InfoCard:         HexColor{249, 250, 251, 255},
+			CardBorder:       HexColor{55, 65, 81, 255},
+			InfoBorder:       HexColor{107, 114, 128, 255},
+			Motivation:       HexColor{239, 68, 68, 180},
+			Performance:      HexColor{34, 197, 94, 180},
+			Colleagues:       HexColor{234, 179, 8, 180},
+			Boss:             HexColor{59, 130, 246, 180},
+			YesOption:        HexColor{34, 197, 94, 255},
+			NoOption:         HexColor{239, 68, 68, 255},
+			SwipeHint:        HexColor{107, 114, 128, 255},
+			RestartButton:    HexColor{59, 130, 246, 255},
+			RestartHover:     HexColor{29, 78, 216, 255},
+			AboutButton:      HexColor{55, 65, 81, 255},
+			AboutHover:       HexColor{75, 85, 101, 255},
+			Panel:            HexColor{55, 65, 81, 255},
+			Overlay:          HexColor{0, 0, 0, 200},
+			TextPrimary:      HexColor{0, 0, 0, 255},
+			TextLight:        HexColor{255, 255, 255, 255},
+			TextOnBackground: HexColor{0, 0, 0, 255},
+		},
+		Fonts: ThemeFonts{
+			Text:  "font.ttf",
+			Emoji: "font2.ttf",
+		},
+		BorderWidth: 1,
+	}
+}
+
+// applyTheme switches the palette, fonts and buttons to the given theme
+func (g *Game) applyTheme(theme *Theme) {
+	currentTheme = theme
+
+	c := theme.Colors
+	colorBackground = color.RGBA(c.Background)
+	colorCard = color.RGBA(c.Card)
+	colorInfoCard = color.RGBA(c.InfoCard)
+	colorCardBorder = color.RGBA(c.CardBorder)
+	colorInfoBorder = color.RGBA(c.InfoBorder)
+	colorMotivation = color.RGBA(c.Motivation)
+	colorPerformance = color.RGBA(c.Performance)
+	colorColleagues = color.RGBA(c.Colleagues)
+	colorBoss = color.RGBA(c.Boss)
+	colorYesOption = color.RGBA(c.YesOption)
+	colorNoOption = color.RGBA(c.NoOption)
+	colorSwipeHint = color.RGBA(c.SwipeHint)
+	colorRestartBtn = color.RGBA(c.RestartButton)
+	colorRestartHover = color.RGBA(c.RestartHover)
+	colorAboutBtn = color.RGBA(c.AboutButton)
+	colorAboutHover = color.RGBA(c.AboutHover)
+	colorPanel = color.RGBA(c.Panel)
+	colorOverlay = color.RGBA(c.Overlay)
+	colorTextPrimary = color.RGBA(c.TextPrimary)
+	colorTextLight = color.RGBA(c.TextLight)
+	colorTextOnBackground = color.RGBA(c.TextOnBackground)
+
+	// Update buttons
+	g.restartButton.Color = colorRestartBtn
+	g.restartButton.HoverColor = colorRestartHover
+	g.restartButton.TextColor = colorTextLight
+	g.aboutButton.Color = colorAboutBtn
+	g.aboutButton.HoverColor = colorAboutHover
+	g.aboutButton.TextColor = colorTextLight
+	g.soundButton.Color = colorAboutBtn
+	g.soundButton.HoverColor = colorAboutHover
+	g.soundButton.TextColor = colorTextLight
+	g.settingsButton.Color = colorAboutBtn
+	g.settingsButton.HoverColor = colorAboutHover
+	g.settingsButton.TextColor = colorTextLight
+	g.undoButton.Color = colorAboutBtn
+	g.undoButton.HoverColor = colorAboutHover
+	g.undoButton.TextColor = colorTextLight
+
+	// Reload fonts only when the theme uses different files
+	if theme.Fonts == loadedFonts {
+		return
+	}
+	if err := loadFonts(theme.Fonts); err != nil {
+		log.Printf("Failed to load theme fonts: %v", err)
+		return
+	}
+	fontScale = 0
+	if err := setFontScale(g.fontScale()); err != nil {
+		log.Printf("Failed to create theme fonts: %v", err)
+	}
+}
+
+// cycleTheme switches to the next theme in the list and remembers it
+func (g *Game) cycleTheme() {
+	if len(g.themes) == 0 {
+		return
+	}
+	g.themeIndex = (g.themeIndex + 1) % len(g.themes)
+	g.settings.Theme = g.themes[g.themeIndex].ID
+	g.applyTheme(g.themes[g.themeIndex])
+	g.saveSettings()
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadThemesDefaults(t *testing.T) {
+	saved := assets
+	assets = fstest.MapFS{"themes.json": {Data: []byte(`[
+		{"id": "partial", "name": "Partial", "colors": {"background": "#000000", "boss": "#11223344"}, "fonts": {"emoji": "emoji.ttf"}, "borderWidth": 0},
+		{"id": "bare"}
+	]`)}}
+	t.Cleanup(func() { assets = saved })
+
+	themes, err := loadThemes("themes.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(themes) != 2 {
+		t.Fatalf("%d themes, want 2", len(themes))
+	}
+
+	light := defaultTheme()
+	partial := themes[0]
+	if partial.ID != "partial" || partial.Name != "Partial" {
+		t.Errorf("theme %s %q, want partial", partial.ID, partial.Name)
+	}
+	if partial.Colors.Background != (HexColor{0, 0, 0, 255}) || partial.Colors.Boss != (HexColor{0x11, 0x22, 0x33, 0x44}) {
+		t.Errorf("given colours %+v, %+v not kept", partial.Colors.Background, partial.Colors.Boss)
+	}
+	if partial.Colors.Card != light.Colors.Card || partial.Colors.TextPrimary != light.Colors.TextPrimary {
+		t.Errorf("missing colours %+v, %+v, want the light theme's", partial.Colors.Card, partial.Colors.TextPrimary)
+	}
+	if partial.Fonts.Text != light.Fonts.Text || partial.Fonts.Emoji != "emoji.ttf" {
+		t.Errorf("fonts %+v, want the light text font and the given emoji font", partial.Fonts)
+	}
+	if partial.BorderWidth != 1 {
+		t.Errorf("borderWidth %v, want 1", partial.BorderWidth)
+	}
+
+	bare := themes[1]
+	if bare.Name != "" || bare.Colors != light.Colors || bare.Shadow != light.Shadow || bare.CardCornerRadius != light.CardCornerRadius {
+		t.Errorf("bare theme %+v, want the light theme without its name", bare)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import "strconv"
+
+// Undo limits offered in the settings, 0 means unlimited
+var undoLimits = []int{1, 3, 5, 0}
+
+// engineSnapshot is the complete engine state before a card was resolved
+type engineSnapshot struct {
+	resources      Resources
+	currentCard    *Card
+	currentCause   int
+	availableCards []*Card
+	uses           map[*Card]int
+	parents        map[*Card]string // ParentCardID, set on the card when it is queued
+	delayedCards   []FollowupCardItem
+	playedCardIDs  int
+	journal        int
+	recordedSteps  int
+	winCardShown   bool
+	rng            rngState
+}
+
+// takeSnapshot saves the engine state in casual mode, before the current
+// card is resolved
+func (g *Game) takeSnapshot() {
+	if g.mode != modeCasual {
+		return
+	}
+
+	uses := make(map[*Card]int)
+	parents := make(map[*Card]string)
+	forEachCard(g.cards, func(card *Card) {
+		uses[card] = card.Uses
+		parents[card] = card.ParentCardID
+	})
+
+	g.undoStack = append(g.undoStack, engineSnapshot{
+		resources:      g.resources,
+		currentCard:    g.currentCard,
+		currentCause:   g.currentCause,
+		availableCards: append([]*Card(nil), g.availableCards...),
+		uses:           uses,
+		parents:        parents,
+		delayedCards:   append([]FollowupCardItem(nil), g.delayedCards...),
+		playedCardIDs:  len(g.playedCardIDs),
+		journal:        len(g.journal),
+		recordedSteps:  len(g.recording.Cards),
+		winCardShown:   g.winCardShown,
+		rng:            g.rngSource.state(),
+	})
+}
+
+// canUndo reports whether the last decision can be taken back. A finished
+// run has already been recorded, so only running games can be undone.
+func (g *Game) canUndo() bool {
+	if g.mode != modeCasual || g.gameOver || g.animating || len(g.undoStack) == 0 {
+		return false
+	}
+	return g.settings.UndoLimit == 0 || g.undoCount < g.settings.UndoLimit
+}
+
+// undo restores the state before the last decision
+func (g *Game) undo() {
+	if !g.canUndo() {
+		return
+	}
+
+	snapshot := g.undoStack[len(g.undoStack)-1]
+	g.undoStack = g.undoStack[:len(g.undoStack)-1]
+	g.undoCount++
+
+	g.resources = snapshot.resources
+	g.currentCard = snapshot.currentCard
+	g.currentCause = snapshot.currentCause
+	g.availableCards = snapshot.availableCards
+	for card, uses := range snapshot.uses {
+		card.Uses = uses
+	}
+	for card, parent := range snapshot.parents {
+		card.ParentCardID = parent
+	}
+	g.delayedCards = snapshot.delayedCards
+	g.playedCardIDs = g.playedCardIDs[:snapshot.playedCardIDs]
+	g.journal = g.journal[:snapshot.journal]
+	g.recording.Cards = g.recording.Cards[:snapshot.recordedSteps]
+	g.recording.Choices = g.recording.Choices[:snapshot.recordedSteps]
+	g.winCardShown = snapshot.winCardShown
+	g.rngSource.restore(snapshot.rng)
+
+	// Show the card again where it started
+	g.cardX = 0
+	g.cardY = 0
+	g.cardRotation = 0
+	g.cardOpacity = 1.0
+	g.stackProgress = 0
+}
+
+// undosLeft describes the remaining undos, e.g. "2" or "∞"
+func (g *Game) undosLeft() string {
+	if g.settings.UndoLimit == 0 {
+		return "∞"
+	}
+	return strconv.Itoa(g.settings.UndoLimit - g.undoCount)
+}
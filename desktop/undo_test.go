@@ -0,0 +1,102 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// Cards with small effects and followups, so a short run changes every
+// part of the engine state without ending
+const undoTestDeck = `{"schemaVersion": 2, "cards": [
+	{"id": "A", "text": "a", "maxUses": 3, "yesEffects": {"motivation": 4}, "yesFollowups": [{"id": "AF", "text": "af", "delay": 0}]},
+	{"id": "B", "text": "b", "maxUses": 3, "noEffects": {"boss": -4}, "noFollowups": [{"id": "BF", "text": "bf", "delay": 3, "probability": 1}, {"id": "BG", "text": "bg", "probability": 1}]},
+	{"id": "C", "text": "c", "maxUses": 3, "yesEffects": {"colleagues": 4}, "noEffects": {"performance": -4}},
+	{"id": "D", "text": "d", "maxUses": 3, "isInfoOnly": true, "effects": {"performance": 4}, "followups": [{"id": "DF", "text": "df", "delay": 2}]}
+]}`
+
+// engineState is what an undo has to restore
+type engineState struct {
+	resources      Resources
+	currentCard    *Card
+	availableCards []*Card
+	uses           map[string]int
+	parents        map[string]string
+	delayedCards   []FollowupCardItem
+	journal        int
+	recording      Replay
+	rng            rngState
+}
+
+// cloneList copies a list, keeping nil and empty lists alike
+func cloneList[T any](list []T) []T {
+	if len(list) == 0 {
+		return nil
+	}
+	return slices.Clone(list)
+}
+
+func captureEngineState(g *Game) engineState {
+	state := engineState{
+		resources:      g.resources,
+		currentCard:    g.currentCard,
+		availableCards: cloneList(g.availableCards),
+		uses:           make(map[string]int),
+		parents:        make(map[string]string),
+		delayedCards:   cloneList(g.delayedCards),
+		journal:        len(g.journal),
+		recording:      g.recording,
+		rng:            g.rngSource.state(),
+	}
+	state.recording.Cards = cloneList(g.recording.Cards)
+	forEachCard(g.cards, func(card *Card) {
+		state.uses[card.ID] = card.Uses
+		state.parents[card.ID] = card.ParentCardID
+	})
+	return state
+}
+
+func TestUndoRestoresEngineState(t *testing.T) {
+	saved := assets
+	assets = fstest.MapFS{"deck.json": {Data: []byte(undoTestDeck)}}
+	t.Cleanup(func() { assets = saved })
+
+	g := &Game{}
+	if err := g.loadCards("deck.json"); err != nil {
+		t.Fatal(err)
+	}
+	g.mode = modeCasual
+	g.resetRun(7, runStart)
+
+	queued := 0
+	for step := range 24 {
+		isYes := step%3 != 1
+		before := captureEngineState(g)
+		g.processCard(isYes)
+		if g.gameOver {
+			t.Fatalf("step %d: test run ended", step)
+		}
+		after := captureEngineState(g)
+		if len(after.delayedCards) > len(before.delayedCards) {
+			queued++
+		}
+
+		g.undo()
+		if got := captureEngineState(g); !reflect.DeepEqual(got, before) {
+			t.Fatalf("step %d: state after undo\n%+v\nwant\n%+v", step, got, before)
+		}
+
+		// Answering again draws the same as the first time
+		g.processCard(isYes)
+		if got := captureEngineState(g); !reflect.DeepEqual(got, after) {
+			t.Fatalf("step %d: state after answering again\n%+v\nwant\n%+v", step, got, after)
+		}
+	}
+	if queued == 0 {
+		t.Error("no followup was queued, the run doesn't test them")
+	}
+	if g.undoCount != 24 {
+		t.Errorf("undoCount = %d, want 24", g.undoCount)
+	}
+}